@@ -0,0 +1,122 @@
+// Command wordcount prints the most common words of its input files
+// (or standard input), like Python's Counter(text.split()).most_common(n).
+//
+// Usage:
+//
+//	wordcount [-n 10] [-format table|csv|json] [-tokenizer space|words]
+//	          [-fold] [-stop file] [-ngram 1] [file ...]
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/wordfreq"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "wordcount:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("wordcount", flag.ContinueOnError)
+	n := fs.Int("n", 10, "number of entries to print (<= 0 prints all)")
+	format := fs.String("format", "table", "output format: table, csv or json")
+	tokenizer := fs.String("tokenizer", "space", "tokenizer: space (split on white space) or words (Unicode words)")
+	fold := fs.Bool("fold", false, "lower-case tokens before counting")
+	stopFile := fs.String("stop", "", "file of white-space separated stop words")
+	ngram := fs.Int("ngram", 1, "count n-grams of this many tokens")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	b := wordfreq.Builder{FoldCase: *fold, N: *ngram}
+	switch *tokenizer {
+	case "space":
+		b.Tokenizer = wordfreq.Whitespace
+	case "words":
+		b.Tokenizer = wordfreq.UnicodeWords
+	default:
+		return fmt.Errorf("unknown tokenizer %q", *tokenizer)
+	}
+	if *stopFile != "" {
+		f, err := os.Open(*stopFile)
+		if err != nil {
+			return err
+		}
+		b.StopWords, err = wordfreq.ReadStopWords(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	c := counter.New[string]()
+	if fs.NArg() == 0 {
+		if err := b.CountInto(c, stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = b.CountInto(c, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return write(stdout, *format, c.MostCommon(*n))
+}
+
+// write renders pairs in the given format.
+func write(w io.Writer, format string, pairs []counter.Pair[string]) error {
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "COUNT\tWORD")
+		for _, p := range pairs {
+			fmt.Fprintf(tw, "%d\t%s\n", p.Count, p.Key)
+		}
+		return tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"word", "count"})
+		for _, p := range pairs {
+			cw.Write([]string{p.Key, strconv.FormatInt(p.Count, 10)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		type entry struct {
+			Word  string `json:"word"`
+			Count int64  `json:"count"`
+		}
+		out := make([]entry, len(pairs))
+		for i, p := range pairs {
+			out[i] = entry{p.Key, p.Count}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFormats(t *testing.T) {
+	const input = "go py go rust go py"
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-n", "2", "-format", "csv"}, "word,count\ngo,3\npy,2\n"},
+		{[]string{"-n", "1", "-format", "json"}, "[\n  {\n    \"word\": \"go\",\n    \"count\": 3\n  }\n]\n"},
+		{[]string{"-n", "2"}, "COUNT  WORD\n3      go\n2      py\n"},
+	}
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(tc.args, strings.NewReader(input), &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.want {
+				t.Fatalf("\n got: %q\nwant: %q", out.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestRunFilesAndStopWords(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "a.txt")
+	stop := filepath.Join(dir, "stop.txt")
+	os.WriteFile(text, []byte("The cat and THE dog."), 0o644)
+	os.WriteFile(stop, []byte("and"), 0o644)
+
+	var out bytes.Buffer
+	args := []string{"-format", "csv", "-tokenizer", "words", "-fold", "-stop", stop, text}
+	if err := run(args, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "word,count\nthe,2\ncat,1\ndog,1\n"; out.String() != want {
+		t.Fatalf("\n got: %q\nwant: %q", out.String(), want)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-format", "xml"},
+		{"-tokenizer", "regex"},
+		{"/does/not/exist"},
+	} {
+		if err := run(args, strings.NewReader("x"), &bytes.Buffer{}); err == nil {
+			t.Fatalf("args %v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package counter
+
+// Multiset arithmetic mirroring Python's Counter operators.
+// All functions below are non-mutating: they return a fresh Counter and
+// keep only strictly positive counts in the result, exactly like CPython.
+
+// Plus returns c + other: counts are summed, non-positive results dropped.
+func (c Counter[T]) Plus(other Counter[T]) Counter[T] {
+	out := New[T]()
+	for k, v := range c {
+		if n := v + other[k]; n > 0 {
+			out[k] = n
+		}
+	}
+	for k, v := range other {
+		if _, ok := c[k]; !ok && v > 0 {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Minus returns c - other: counts are subtracted, non-positive results dropped.
+// Unlike Subtract, neither operand is modified.
+func (c Counter[T]) Minus(other Counter[T]) Counter[T] {
+	out := New[T]()
+	for k, v := range c {
+		if n := v - other[k]; n > 0 {
+			out[k] = n
+		}
+	}
+	for k, v := range other {
+		if _, ok := c[k]; !ok && v < 0 {
+			out[k] = -v
+		}
+	}
+	return out
+}
+
+// Union returns c | other: the maximum of both counts, non-positive dropped.
+func (c Counter[T]) Union(other Counter[T]) Counter[T] {
+	out := New[T]()
+	for k, v := range c {
+		n := max(v, other[k])
+		if n > 0 {
+			out[k] = n
+		}
+	}
+	for k, v := range other {
+		if _, ok := c[k]; !ok && v > 0 {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Intersect returns c & other: the minimum of both counts, non-positive dropped.
+func (c Counter[T]) Intersect(other Counter[T]) Counter[T] {
+	out := New[T]()
+	for k, v := range c {
+		n := min(v, other[k])
+		if n > 0 {
+			out[k] = n
+		}
+	}
+	return out
+}
+
+// Positive returns +c: a copy holding only the positive counts.
+func (c Counter[T]) Positive() Counter[T] {
+	out := New[T]()
+	for k, v := range c {
+		if v > 0 {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Negative returns -c: negative counts flipped to positive, everything else dropped.
+func (c Counter[T]) Negative() Counter[T] {
+	out := New[T]()
+	for k, v := range c {
+		if v < 0 {
+			out[k] = -v
+		}
+	}
+	return out
+}
+
+//// Thread-safe variant ////////////////////////////////////////////////////////
+
+// withPair runs op on a consistent view of s and other.
+// other is snapshotted under its own lock first, so the two mutexes are never
+// held at the same time and callers cannot deadlock by passing operands in
+// opposite orders.
+func withPair[T comparable, R any](s, other *SafeCounter[T], op func(a, b Counter[T]) R) R {
+	if s == other {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return op(s.c, s.c)
+	}
+	snap := other.Clone()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return op(s.c, snap)
+}
+
+// binary is withPair for operators that produce a new counter.
+func (s *SafeCounter[T]) binary(other *SafeCounter[T], op func(a, b Counter[T]) Counter[T]) *SafeCounter[T] {
+	return &SafeCounter[T]{c: withPair(s, other, op)}
+}
+
+// Plus is the locked counterpart of Counter.Plus.
+func (s *SafeCounter[T]) Plus(other *SafeCounter[T]) *SafeCounter[T] {
+	return s.binary(other, Counter[T].Plus)
+}
+
+// Minus is the locked counterpart of Counter.Minus.
+func (s *SafeCounter[T]) Minus(other *SafeCounter[T]) *SafeCounter[T] {
+	return s.binary(other, Counter[T].Minus)
+}
+
+// Union is the locked counterpart of Counter.Union.
+func (s *SafeCounter[T]) Union(other *SafeCounter[T]) *SafeCounter[T] {
+	return s.binary(other, Counter[T].Union)
+}
+
+// Intersect is the locked counterpart of Counter.Intersect.
+func (s *SafeCounter[T]) Intersect(other *SafeCounter[T]) *SafeCounter[T] {
+	return s.binary(other, Counter[T].Intersect)
+}
+
+// Positive is the locked counterpart of Counter.Positive.
+func (s *SafeCounter[T]) Positive() *SafeCounter[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &SafeCounter[T]{c: s.c.Positive()}
+}
+
+// Negative is the locked counterpart of Counter.Negative.
+func (s *SafeCounter[T]) Negative() *SafeCounter[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &SafeCounter[T]{c: s.c.Negative()}
+}
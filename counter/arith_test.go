@@ -0,0 +1,75 @@
+package counter_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestArithmeticMatchesPython(t *testing.T) {
+	// 与 CPython 对照：
+	// >>> a = Counter(a=3, b=1, c=-2); b = Counter(a=1, b=2, d=-1, e=4)
+	a := counter.Counter[string]{"a": 3, "b": 1, "c": -2}
+	b := counter.Counter[string]{"a": 1, "b": 2, "d": -1, "e": 4}
+
+	cases := []struct {
+		name string
+		got  counter.Counter[string]
+		want map[string]int64
+	}{
+		{"a + b", a.Plus(b), map[string]int64{"a": 4, "b": 3, "e": 4}},
+		{"a - b", a.Minus(b), map[string]int64{"a": 2, "d": 1}},
+		{"a | b", a.Union(b), map[string]int64{"a": 3, "b": 2, "e": 4}},
+		{"a & b", a.Intersect(b), map[string]int64{"a": 1, "b": 1}},
+		{"+a", a.Positive(), map[string]int64{"a": 3, "b": 1}},
+		{"-a", a.Negative(), map[string]int64{"c": 2}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.got) != len(tc.want) {
+				t.Fatalf("got %v want %v", tc.got, tc.want)
+			}
+			assertMapEq(t, tc.got, tc.want)
+		})
+	}
+
+	// 操作数保持不变
+	assertMapEq(t, a, map[string]int64{"a": 3, "b": 1, "c": -2})
+	assertMapEq(t, b, map[string]int64{"a": 1, "b": 2, "d": -1, "e": 4})
+}
+
+func TestArithmeticNilOperands(t *testing.T) {
+	var empty counter.Counter[string]
+	c := counter.Counter[string]{"x": 2}
+	assertMapEq(t, empty.Plus(c), map[string]int64{"x": 2})
+	assertMapEq(t, c.Minus(empty), map[string]int64{"x": 2})
+	assertMapEq(t, empty.Union(empty), map[string]int64{})
+	assertMapEq(t, c.Intersect(empty), map[string]int64{})
+}
+
+func TestSafeCounterArithmetic(t *testing.T) {
+	a := counter.NewSafe[string]()
+	a.Update(counter.Counter[string]{"a": 3, "b": 1})
+	b := counter.NewSafe[string]()
+	b.Update(counter.Counter[string]{"a": 1, "c": 2})
+
+	assertMapEq(t, a.Plus(b).Clone(), map[string]int64{"a": 4, "b": 1, "c": 2})
+	assertMapEq(t, a.Minus(b).Clone(), map[string]int64{"a": 2, "b": 1})
+	assertMapEq(t, a.Union(b).Clone(), map[string]int64{"a": 3, "b": 1, "c": 2})
+	assertMapEq(t, a.Intersect(b).Clone(), map[string]int64{"a": 1})
+	assertMapEq(t, a.Minus(a).Clone(), map[string]int64{})
+}
+
+func TestSafeCounterArithmeticNoDeadlock(t *testing.T) {
+	a, b := counter.NewSafe[int](), counter.NewSafe[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() { defer wg.Done(); a.Plus(b) }()
+		go func() { defer wg.Done(); b.Union(a) }()
+		go func(v int) { defer wg.Done(); a.Add(v); b.Add(v) }(i)
+	}
+	wg.Wait()
+	assertEqual(t, a.Total(), int64(50))
+}
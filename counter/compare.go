@@ -0,0 +1,74 @@
+package counter
+
+// Rich comparisons with Python 3.10 semantics: counters are treated as
+// multisets and a missing key compares as a zero count, so
+// Counter{"a": 1, "b": 0} equals Counter{"a": 1}.
+
+// Equal reports whether c and other hold the same count for every key.
+func (c Counter[T]) Equal(other Counter[T]) bool {
+	for k, v := range c {
+		if other[k] != v {
+			return false
+		}
+	}
+	for k, v := range other {
+		if c[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset reports whether c <= other, i.e. every count in c is
+// less than or equal to the matching count in other.
+func (c Counter[T]) IsSubset(other Counter[T]) bool {
+	for k, v := range c {
+		if v > other[k] {
+			return false
+		}
+	}
+	for k, v := range other {
+		if c[k] > v {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubset reports whether c < other: a subset that is not equal.
+func (c Counter[T]) IsProperSubset(other Counter[T]) bool {
+	return c.IsSubset(other) && !c.Equal(other)
+}
+
+// IsSuperset reports whether c >= other.
+func (c Counter[T]) IsSuperset(other Counter[T]) bool { return other.IsSubset(c) }
+
+// IsProperSuperset reports whether c > other.
+func (c Counter[T]) IsProperSuperset(other Counter[T]) bool { return other.IsProperSubset(c) }
+
+//// Thread-safe variant ////////////////////////////////////////////////////////
+
+// Equal is the locked counterpart of Counter.Equal.
+func (s *SafeCounter[T]) Equal(other *SafeCounter[T]) bool {
+	return withPair(s, other, Counter[T].Equal)
+}
+
+// IsSubset is the locked counterpart of Counter.IsSubset.
+func (s *SafeCounter[T]) IsSubset(other *SafeCounter[T]) bool {
+	return withPair(s, other, Counter[T].IsSubset)
+}
+
+// IsProperSubset is the locked counterpart of Counter.IsProperSubset.
+func (s *SafeCounter[T]) IsProperSubset(other *SafeCounter[T]) bool {
+	return withPair(s, other, Counter[T].IsProperSubset)
+}
+
+// IsSuperset is the locked counterpart of Counter.IsSuperset.
+func (s *SafeCounter[T]) IsSuperset(other *SafeCounter[T]) bool {
+	return withPair(s, other, Counter[T].IsSuperset)
+}
+
+// IsProperSuperset is the locked counterpart of Counter.IsProperSuperset.
+func (s *SafeCounter[T]) IsProperSuperset(other *SafeCounter[T]) bool {
+	return withPair(s, other, Counter[T].IsProperSuperset)
+}
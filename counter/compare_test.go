@@ -0,0 +1,46 @@
+package counter_test
+
+import (
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestCompareMissingIsZero(t *testing.T) {
+	type C = counter.Counter[string]
+	cases := []struct {
+		name                    string
+		a, b                    C
+		eq, sub, properSub, sup bool
+	}{
+		{"zero vs missing", C{"a": 1, "b": 0}, C{"a": 1}, true, true, false, true},
+		{"strict subset", C{"a": 1}, C{"a": 2, "b": 1}, false, true, true, false},
+		{"superset", C{"a": 3, "b": 1}, C{"a": 2}, false, false, false, true},
+		{"incomparable", C{"a": 1}, C{"b": 1}, false, false, false, false},
+		{"negative other", C{}, C{"x": -1}, false, false, false, true},
+		{"both empty", nil, C{}, true, true, false, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqual(t, tc.a.Equal(tc.b), tc.eq)
+			assertEqual(t, tc.a.IsSubset(tc.b), tc.sub)
+			assertEqual(t, tc.a.IsProperSubset(tc.b), tc.properSub)
+			assertEqual(t, tc.a.IsSuperset(tc.b), tc.sup)
+			assertEqual(t, tc.b.IsProperSuperset(tc.a), tc.properSub)
+		})
+	}
+}
+
+func TestSafeCounterCompare(t *testing.T) {
+	stock := counter.NewSafe[string]()
+	stock.Update(counter.Counter[string]{"apple": 5, "pear": 2})
+	order := counter.NewSafe[string]()
+	order.Update(counter.Counter[string]{"apple": 2})
+
+	assertEqual(t, order.IsSubset(stock), true)
+	assertEqual(t, order.IsProperSubset(stock), true)
+	assertEqual(t, stock.IsSuperset(order), true)
+	assertEqual(t, stock.IsProperSuperset(order), true)
+	assertEqual(t, stock.Equal(stock), true)
+	assertEqual(t, stock.Equal(order), false)
+}
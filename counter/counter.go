@@ -2,7 +2,6 @@ package counter
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"sync"
 )
@@ -154,50 +153,37 @@ func (c Counter[T]) Items() []Pair[T] {
 
 // MostCommon returns top-n pairs sorted by Count desc (then by key asc for stability).
 // If n <= 0 or n >= len(c), returns all sorted.
+// Keys of built-in ordered types tie-break by their natural order; other keys
+// fall back to comparing fmt.Sprint output. Use MostCommonFunc or
+// MostCommonOrdered to control the key ordering explicitly.
 func (c Counter[T]) MostCommon(n int) []Pair[T] {
-	items := c.Items()
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Count == items[j].Count {
-			// best effort: for comparable T we try fmt.Sprint for stable tie-break
-			return fmt.Sprint(items[i].Key) < fmt.Sprint(items[j].Key)
-		}
-		return items[i].Count > items[j].Count
-	})
-	if n <= 0 || n >= len(items) {
-		return items
-	}
-	return items[:n]
+	return c.MostCommonFunc(n, defaultCompare[T]())
 }
 
 // Elements returns a slice that repeats each element according to its count (like Python's elements()).
 // Negative or zero counts are ignored.
+// It panics with ErrOverflow if the result exceeds what the runtime can
+// allocate; smaller but still huge results can exhaust memory. For large or
+// untrusted counts use TryElementsMax to bound the size, or ElementsSeq to
+// stream.
 func (c Counter[T]) Elements() []T {
-	total := 0
-	for _, v := range c {
-		if v > 0 {
-			total += int(v)
-		}
-	}
-	out := make([]T, 0, total)
-	for k, v := range c {
-		for i := int64(0); i < v; i++ {
-			out = append(out, k)
-		}
+	out, err := c.TryElements()
+	if err != nil {
+		panic(err)
 	}
 	return out
 }
 
-// String implements fmt.Stringer for readable debug print, e.g. Counter{'a': 2, 'b': 1}
+// String implements fmt.Stringer for readable debug print, e.g. Counter{a:2, b:1}.
+// Entries are listed in MostCommon order so the output is deterministic.
 func (c Counter[T]) String() string {
 	var b strings.Builder
 	b.WriteString("Counter{")
-	first := true
-	for k, v := range c {
-		if !first {
+	for i, p := range c.MostCommon(0) {
+		if i > 0 {
 			b.WriteString(", ")
 		}
-		first = false
-		fmt.Fprintf(&b, "%v:%d", k, v)
+		fmt.Fprintf(&b, "%v:%d", p.Key, p.Count)
 	}
 	b.WriteString("}")
 	return b.String()
@@ -208,8 +194,9 @@ func (c Counter[T]) String() string {
 // SafeCounter wraps Counter with a RWMutex for concurrent use.
 // Rule of thumb: use SafeCounter only if multiple goroutines WRITE concurrently.
 type SafeCounter[T comparable] struct {
-	mu sync.RWMutex
-	c  Counter[T]
+	mu    sync.RWMutex
+	c     Counter[T]
+	watch *watchState[T] // nil until the first Watch
 }
 
 // NewSafe creates a SafeCounter.
@@ -226,39 +213,31 @@ func (s *SafeCounter[T]) Get(k T) int64 {
 	return s.c[k]
 }
 func (s *SafeCounter[T]) Set(k T, n int64) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.c.Set(k, n)
+	s.writeKey(k, func() { s.c.Set(k, n) })
 }
 func (s *SafeCounter[T]) Add(k T) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.c.Add(k)
+	s.writeKey(k, func() { s.c.Add(k) })
 }
 func (s *SafeCounter[T]) AddN(k T, n int64) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.c.AddN(k, n)
+	s.writeKey(k, func() { s.c.AddN(k, n) })
 }
 func (s *SafeCounter[T]) Update(other Counter[T]) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.c.Update(other)
+	s.writeKeys(keysOf(other), func() { s.c.Update(other) })
 }
 func (s *SafeCounter[T]) UpdateSlice(xs []T) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.c.UpdateSlice(xs)
+	keys := func(yield func(T)) {
+		for _, x := range xs {
+			yield(x)
+		}
+	}
+	s.writeKeys(keys, func() { s.c.UpdateSlice(xs) })
 }
 func (s *SafeCounter[T]) Subtract(other Counter[T]) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.c.Subtract(other)
+	s.writeKeys(keysOf(other), func() { s.c.Subtract(other) })
 }
-func (s *SafeCounter[T]) Delete(k T) int64 {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return s.c.Delete(k)
+func (s *SafeCounter[T]) Delete(k T) (prev int64) {
+	s.writeKey(k, func() { prev = s.c.Delete(k) })
+	return prev
 }
 func (s *SafeCounter[T]) Clean() {
 	s.mu.Lock()
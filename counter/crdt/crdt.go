@@ -0,0 +1,235 @@
+// Package crdt provides state-based replicated counters (CRDTs) built on
+// counter.Counter.
+//
+// Every replica keeps, for each replica ID it has heard of, a Counter of the
+// increments made there. A replica only ever changes its own entry, so two
+// states merge by taking the per-replica, per-key maximum. Merge is
+// commutative, associative and idempotent: replicas that have seen the same
+// updates agree on every value, whatever order and however often states were
+// exchanged.
+//
+// The types are not safe for concurrent use; guard them with a mutex when
+// several goroutines share one replica.
+package crdt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// GCounter is a grow-only counter: per-key counts can only increase.
+type GCounter[T comparable] struct {
+	id    string
+	state map[string]counter.Counter[T]
+}
+
+// NewGCounter creates an empty GCounter for the given replica ID.
+// IDs must be unique across the replicas that merge with each other.
+func NewGCounter[T comparable](replica string) *GCounter[T] {
+	return &GCounter[T]{id: replica, state: make(map[string]counter.Counter[T])}
+}
+
+// Replica returns the replica ID this GCounter increments under.
+func (g *GCounter[T]) Replica() string { return g.id }
+
+// Increment adds 1 to key.
+func (g *GCounter[T]) Increment(k T) { g.IncrementN(k, 1) }
+
+// IncrementN adds n to key. It panics if n is negative; n == 0 is a no-op.
+func (g *GCounter[T]) IncrementN(k T, n int64) {
+	if n < 0 {
+		panic("crdt: GCounter.IncrementN with negative count")
+	}
+	if n == 0 {
+		return
+	}
+	own := g.state[g.id]
+	if own == nil {
+		own = counter.New[T]()
+		g.state[g.id] = own
+	}
+	own.AddN(k, n)
+}
+
+// Value returns the count of key summed over all replicas.
+func (g *GCounter[T]) Value(k T) int64 {
+	var sum int64
+	for _, c := range g.state {
+		sum += c.Get(k)
+	}
+	return sum
+}
+
+// Counter returns the summed counts of every key.
+func (g *GCounter[T]) Counter() counter.Counter[T] {
+	out := counter.New[T]()
+	for _, c := range g.state {
+		out.Update(c)
+	}
+	return out
+}
+
+// Total returns the sum of all counts.
+func (g *GCounter[T]) Total() int64 {
+	var sum int64
+	for _, c := range g.state {
+		sum += c.Total()
+	}
+	return sum
+}
+
+// Merge folds other's state into g, keeping the larger count for every
+// (replica, key). The replica ID of g is unchanged.
+func (g *GCounter[T]) Merge(other *GCounter[T]) {
+	for id, theirs := range other.state {
+		ours := g.state[id]
+		if ours == nil {
+			ours = counter.New[T]()
+			g.state[id] = ours
+		}
+		for k, n := range theirs {
+			if n > ours[k] {
+				ours[k] = n
+			}
+		}
+	}
+}
+
+// Clone returns a deep copy of g.
+func (g *GCounter[T]) Clone() *GCounter[T] {
+	out := NewGCounter[T](g.id)
+	for id, c := range g.state {
+		out.state[id] = c.Clone()
+	}
+	return out
+}
+
+// gJSON is the wire form of a GCounter:
+//
+//	{"replica":"a","state":{"a":{"x":3},"b":{"x":1,"y":2}}}
+//
+// Per-replica counters use counter.Counter's JSON layout.
+type gJSON[T comparable] struct {
+	Replica string                        `json:"replica"`
+	State   map[string]counter.Counter[T] `json:"state"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (g *GCounter[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gJSON[T]{g.id, g.state})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing g's replica ID and
+// state. To combine a received state with a local replica, decode it into a
+// fresh GCounter and Merge.
+func (g *GCounter[T]) UnmarshalJSON(data []byte) error {
+	var v gJSON[T]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	dec, err := fromState(v.Replica, v.State)
+	if err != nil {
+		return err
+	}
+	*g = *dec
+	return nil
+}
+
+// fromState validates a decoded state and wraps it in a GCounter.
+func fromState[T comparable](replica string, state map[string]counter.Counter[T]) (*GCounter[T], error) {
+	g := NewGCounter[T](replica)
+	for id, c := range state {
+		for k, n := range c {
+			if n < 0 {
+				return nil, fmt.Errorf("crdt: negative count %d for key %v at replica %q", n, k, id)
+			}
+		}
+		if c == nil {
+			c = counter.New[T]()
+		}
+		g.state[id] = c
+	}
+	return g, nil
+}
+
+// PNCounter supports increments and decrements. It pairs two GCounters,
+// one for each direction, and reports their difference.
+type PNCounter[T comparable] struct {
+	p, n *GCounter[T]
+}
+
+// NewPNCounter creates an empty PNCounter for the given replica ID.
+func NewPNCounter[T comparable](replica string) *PNCounter[T] {
+	return &PNCounter[T]{p: NewGCounter[T](replica), n: NewGCounter[T](replica)}
+}
+
+// Replica returns the replica ID this PNCounter updates under.
+func (c *PNCounter[T]) Replica() string { return c.p.id }
+
+// Increment adds 1 to key.
+func (c *PNCounter[T]) Increment(k T) { c.p.IncrementN(k, 1) }
+
+// IncrementN adds n to key. It panics if n is negative.
+func (c *PNCounter[T]) IncrementN(k T, n int64) { c.p.IncrementN(k, n) }
+
+// Decrement subtracts 1 from key.
+func (c *PNCounter[T]) Decrement(k T) { c.n.IncrementN(k, 1) }
+
+// DecrementN subtracts n from key. It panics if n is negative.
+func (c *PNCounter[T]) DecrementN(k T, n int64) { c.n.IncrementN(k, n) }
+
+// Value returns the count of key (can be negative).
+func (c *PNCounter[T]) Value(k T) int64 { return c.p.Value(k) - c.n.Value(k) }
+
+// Counter returns the net count of every key. Keys whose increments and
+// decrements cancel out are omitted.
+func (c *PNCounter[T]) Counter() counter.Counter[T] {
+	out := c.p.Counter()
+	out.Subtract(c.n.Counter())
+	return out
+}
+
+// Merge folds other's state into c.
+func (c *PNCounter[T]) Merge(other *PNCounter[T]) {
+	c.p.Merge(other.p)
+	c.n.Merge(other.n)
+}
+
+// Clone returns a deep copy of c.
+func (c *PNCounter[T]) Clone() *PNCounter[T] {
+	return &PNCounter[T]{p: c.p.Clone(), n: c.n.Clone()}
+}
+
+// pnJSON is the wire form of a PNCounter:
+//
+//	{"replica":"a","p":{"a":{"x":3}},"n":{"a":{"x":1}}}
+type pnJSON[T comparable] struct {
+	Replica string                        `json:"replica"`
+	P       map[string]counter.Counter[T] `json:"p"`
+	N       map[string]counter.Counter[T] `json:"n"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *PNCounter[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pnJSON[T]{c.p.id, c.p.state, c.n.state})
+}
+
+// UnmarshalJSON implements json.Unmarshaler; see GCounter.UnmarshalJSON.
+func (c *PNCounter[T]) UnmarshalJSON(data []byte) error {
+	var v pnJSON[T]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	p, err := fromState(v.Replica, v.P)
+	if err != nil {
+		return err
+	}
+	n, err := fromState(v.Replica, v.N)
+	if err != nil {
+		return err
+	}
+	c.p, c.n = p, n
+	return nil
+}
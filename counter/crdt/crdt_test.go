@@ -0,0 +1,212 @@
+package crdt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/crdt"
+)
+
+func TestGCounterBasics(t *testing.T) {
+	a := crdt.NewGCounter[string]("a")
+	b := crdt.NewGCounter[string]("b")
+	a.Increment("x")
+	a.IncrementN("x", 2)
+	b.IncrementN("x", 5)
+	b.Increment("y")
+
+	a.Merge(b)
+	a.Merge(b) // 幂等
+	if a.Value("x") != 8 || a.Value("y") != 1 || a.Total() != 9 {
+		t.Fatalf("after merge: %v", a.Counter())
+	}
+	if a.Replica() != "a" {
+		t.Fatalf("replica changed to %q", a.Replica())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("negative IncrementN should panic")
+		}
+	}()
+	a.IncrementN("x", -1)
+}
+
+func TestPNCounterBasics(t *testing.T) {
+	a := crdt.NewPNCounter[string]("a")
+	b := crdt.NewPNCounter[string]("b")
+	a.IncrementN("stock", 10)
+	b.DecrementN("stock", 3)
+	b.Increment("gone")
+	a.Decrement("gone")
+	a.Merge(b)
+
+	if a.Value("stock") != 7 || a.Value("gone") != 0 {
+		t.Fatalf("values: stock=%d gone=%d", a.Value("stock"), a.Value("gone"))
+	}
+	// 增减抵消的键不出现在 Counter() 中
+	if got := a.Counter(); len(got) != 1 || got["stock"] != 7 {
+		t.Fatalf("Counter() = %v", got)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	a := crdt.NewPNCounter[int]("a")
+	a.IncrementN(404, 3)
+	a.Decrement(500)
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"replica":"a","p":{"a":[[404,3]]},"n":{"a":[[500,1]]}}`
+	if string(data) != want {
+		t.Fatalf("got %s\nwant %s", data, want)
+	}
+
+	var b crdt.PNCounter[int]
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Replica() != "a" || b.Value(404) != 3 || b.Value(500) != -1 {
+		t.Fatalf("decoded %s: %v", b.Replica(), b.Counter())
+	}
+
+	var g crdt.GCounter[string]
+	if err := json.Unmarshal([]byte(`{"replica":"r","state":{"r":{"x":-1}}}`), &g); err == nil {
+		t.Fatal("want error for negative count")
+	}
+}
+
+// replicas runs a random workload on n replicas, each syncing now and then
+// with a random peer, and returns their final states.
+func replicas(seed uint64, n int) []*crdt.PNCounter[string] {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	rs := make([]*crdt.PNCounter[string], n)
+	for i := range rs {
+		rs[i] = crdt.NewPNCounter[string](fmt.Sprintf("r%d", i))
+	}
+	keys := []string{"a", "b", "c", "d"}
+	for step := 0; step < 300; step++ {
+		r := rs[rng.IntN(n)]
+		k := keys[rng.IntN(len(keys))]
+		switch rng.IntN(5) {
+		case 0:
+			r.Merge(rs[rng.IntN(n)])
+		case 1, 2:
+			r.DecrementN(k, rng.Int64N(4))
+		default:
+			r.IncrementN(k, rng.Int64N(5))
+		}
+	}
+	return rs
+}
+
+func encode(t *testing.T, c *crdt.PNCounter[string]) string {
+	t.Helper()
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// mergeAll merges rs into a fresh replica in the given order.
+func mergeAll(rs []*crdt.PNCounter[string], order []int) *crdt.PNCounter[string] {
+	out := crdt.NewPNCounter[string]("sink")
+	for _, i := range order {
+		out.Merge(rs[i])
+	}
+	return out
+}
+
+func permutations(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var out [][]int
+	for _, p := range permutations(n - 1) {
+		for i := 0; i <= len(p); i++ {
+			q := append(append(append([]int{}, p[:i]...), n-1), p[i:]...)
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+func TestMergeOrderDoesNotMatter(t *testing.T) {
+	for seed := uint64(0); seed < 20; seed++ {
+		rs := replicas(seed, 4)
+		want := mergeAll(rs, []int{0, 1, 2, 3})
+		for _, order := range permutations(4) {
+			if got := mergeAll(rs, order); encode(t, got) != encode(t, want) {
+				t.Fatalf("seed %d order %v: %v != %v", seed, order, got.Counter(), want.Counter())
+			}
+		}
+	}
+}
+
+func TestMergeLaws(t *testing.T) {
+	for seed := uint64(0); seed < 50; seed++ {
+		rs := replicas(seed, 3)
+		a, b, c := rs[0], rs[1], rs[2]
+
+		// 交换律：a∪b == b∪a（比较值，副本 ID 不同）
+		ab, ba := a.Clone(), b.Clone()
+		ab.Merge(b)
+		ba.Merge(a)
+		if !ab.Counter().Equal(ba.Counter()) {
+			t.Fatalf("seed %d: not commutative: %v vs %v", seed, ab.Counter(), ba.Counter())
+		}
+
+		// 结合律：(a∪b)∪c == a∪(b∪c)
+		left := ab.Clone()
+		left.Merge(c)
+		bc := b.Clone()
+		bc.Merge(c)
+		right := a.Clone()
+		right.Merge(bc)
+		if encode(t, left) != encode(t, right) {
+			t.Fatalf("seed %d: not associative", seed)
+		}
+
+		// 幂等：a∪a == a，且合并后再合并旧状态不变
+		aa := a.Clone()
+		aa.Merge(a)
+		if encode(t, aa) != encode(t, a) {
+			t.Fatalf("seed %d: not idempotent", seed)
+		}
+		again := left.Clone()
+		again.Merge(a)
+		if encode(t, again) != encode(t, left) {
+			t.Fatalf("seed %d: merging an older state changed the result", seed)
+		}
+	}
+}
+
+func TestMergeMatchesSequentialCount(t *testing.T) {
+	// 所有副本互相同步后，值等于把所有操作放在一个普通 Counter 上执行
+	a := crdt.NewPNCounter[string]("a")
+	b := crdt.NewPNCounter[string]("b")
+	ref := counter.New[string]()
+	for i := 0; i < 100; i++ {
+		k := string(rune('a' + i%3))
+		if i%4 == 0 {
+			b.Decrement(k)
+			ref.AddN(k, -1)
+		} else {
+			a.Increment(k)
+			ref.Add(k)
+		}
+		if i%10 == 0 {
+			a.Merge(b)
+		}
+	}
+	a.Merge(b)
+	b.Merge(a)
+	if !a.Counter().Equal(ref) || !b.Counter().Equal(ref) {
+		t.Fatalf("a=%v b=%v want %v", a.Counter(), b.Counter(), ref)
+	}
+}
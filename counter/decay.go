@@ -0,0 +1,158 @@
+package counter
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+//// Exponentially decayed variant //////////////////////////////////////////////
+
+// DecayMinScore is the score below which Decayed drops keys when it compacts
+// itself automatically.
+const DecayMinScore = 1e-3
+
+// ScoredPair holds a (Key, Score) entry of a Decayed counter.
+type ScoredPair[T comparable] struct {
+	Key   T
+	Score float64
+}
+
+// Decayed is a counter whose scores halve every halfLife, for "trending"
+// rankings. Decay is lazy: scores are stored relative to a landmark time and
+// scaled on read, so writes are O(1) and no background goroutine is needed.
+// Once per halfLife a write also compacts away keys whose score fell below
+// DecayMinScore. Decayed is safe for concurrent use.
+type Decayed[T comparable] struct {
+	mu        sync.Mutex
+	clock     Clock
+	halfLife  time.Duration
+	landmark  time.Time
+	compacted time.Time
+	scores    map[T]float64 // score at landmark time
+}
+
+// NewDecayed creates a decayed counter. A nil clock means the system clock.
+// It panics if halfLife is not positive.
+func NewDecayed[T comparable](halfLife time.Duration, clock Clock) *Decayed[T] {
+	if halfLife <= 0 {
+		panic(fmt.Sprintf("counter: invalid half-life %v", halfLife))
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+	now := clock.Now()
+	return &Decayed[T]{
+		clock:     clock,
+		halfLife:  halfLife,
+		landmark:  now,
+		compacted: now,
+		scores:    make(map[T]float64),
+	}
+}
+
+// halvings returns how many half-lives separate now from the landmark.
+func (d *Decayed[T]) halvings(now time.Time) float64 {
+	return float64(now.Sub(d.landmark)) / float64(d.halfLife)
+}
+
+// rebase moves the landmark to now so stored scores stay in float range.
+// Caller holds mu.
+func (d *Decayed[T]) rebase(now time.Time) {
+	f := math.Exp2(-d.halvings(now))
+	for k, v := range d.scores {
+		d.scores[k] = v * f
+	}
+	d.landmark = now
+}
+
+// Add adds 1 to key's score.
+func (d *Decayed[T]) Add(k T) { d.AddN(k, 1) }
+
+// AddN adds n to key's current score.
+func (d *Decayed[T]) AddN(k T, n float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.clock.Now()
+	// compact rebases, so after this the landmark is less than one half-life
+	// old and the scale factor below stays under 2.
+	if now.Sub(d.compacted) >= d.halfLife {
+		d.compact(now, DecayMinScore)
+	}
+	d.scores[k] += n * math.Exp2(d.halvings(now))
+}
+
+// Get returns key's current decayed score (0 if missing).
+func (d *Decayed[T]) Get(k T) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.scores[k] * math.Exp2(-d.halvings(d.clock.Now()))
+}
+
+// Len returns the number of tracked keys, including ones not yet compacted.
+func (d *Decayed[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.scores)
+}
+
+// Total returns the sum of all current scores.
+func (d *Decayed[T]) Total() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var s float64
+	for _, v := range d.scores {
+		s += v
+	}
+	return s * math.Exp2(-d.halvings(d.clock.Now()))
+}
+
+// MostCommon returns the top-n keys by current score (then by key for
+// stability, as Counter.MostCommon). If n <= 0 or n >= Len, all are returned.
+func (d *Decayed[T]) MostCommon(n int) []ScoredPair[T] {
+	d.mu.Lock()
+	f := math.Exp2(-d.halvings(d.clock.Now()))
+	items := make([]ScoredPair[T], 0, len(d.scores))
+	for k, v := range d.scores {
+		items = append(items, ScoredPair[T]{k, v * f})
+	}
+	d.mu.Unlock()
+
+	keyCmp := defaultCompare[T]()
+	slices.SortFunc(items, func(a, b ScoredPair[T]) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return keyCmp(a.Key, b.Key)
+	})
+	if n <= 0 || n >= len(items) {
+		return items
+	}
+	return items[:n]
+}
+
+// Compact drops keys whose current score is below minScore and returns how
+// many were removed.
+func (d *Decayed[T]) Compact(minScore float64) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.compact(d.clock.Now(), minScore)
+}
+
+func (d *Decayed[T]) compact(now time.Time, minScore float64) int {
+	d.rebase(now)
+	removed := 0
+	for k, v := range d.scores {
+		if v < minScore {
+			delete(d.scores, k)
+			removed++
+		}
+	}
+	d.compacted = now
+	return removed
+}
@@ -0,0 +1,79 @@
+package counter_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func assertNear(t *testing.T, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9*math.Max(1, math.Abs(want)) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+}
+
+func TestDecayedHalfLife(t *testing.T) {
+	clk := newFakeClock()
+	d := counter.NewDecayed[string](time.Hour, clk)
+	d.AddN("go", 8)
+	d.Add("py")
+	assertNear(t, d.Get("go"), 8)
+
+	clk.Advance(time.Hour)
+	assertNear(t, d.Get("go"), 4)
+	assertNear(t, d.Total(), 4.5)
+
+	d.AddN("go", 1)
+	clk.Advance(2 * time.Hour)
+	assertNear(t, d.Get("go"), 5.0/4)
+	assertNear(t, d.Get("missing"), 0)
+}
+
+func TestDecayedTrendingOrder(t *testing.T) {
+	clk := newFakeClock()
+	d := counter.NewDecayed[string](10*time.Minute, clk)
+	d.AddN("old", 100) // 昨天的热点
+	clk.Advance(time.Hour)
+	d.AddN("new", 5)
+	d.AddN("newer", 5)
+
+	top := d.MostCommon(2)
+	assertEqual(t, top[0].Key, "new")
+	assertEqual(t, top[1].Key, "newer")
+	assertNear(t, top[0].Score, 5)
+	assertEqual(t, len(d.MostCommon(0)), 3)
+}
+
+func TestDecayedCompaction(t *testing.T) {
+	clk := newFakeClock()
+	d := counter.NewDecayed[int](time.Minute, clk)
+	for i := 0; i < 100; i++ {
+		d.Add(i)
+	}
+	// 20 个半衰期后分数 ≈ 1e-6，低于 DecayMinScore，下一次写入时自动压缩
+	clk.Advance(20 * time.Minute)
+	d.Add(1000)
+	assertEqual(t, d.Len(), 1)
+	assertNear(t, d.Get(1000), 1)
+
+	d.AddN(7, 0.5)
+	clk.Advance(time.Minute)
+	assertEqual(t, d.Compact(0.3), 1)
+	assertEqual(t, d.Len(), 1)
+}
+
+func TestDecayedLongIdleNoOverflow(t *testing.T) {
+	clk := newFakeClock()
+	d := counter.NewDecayed[string](time.Second, clk)
+	d.Add("a")
+	clk.Advance(24 * time.Hour) // 86400 个半衰期
+	d.Add("b")
+	assertNear(t, d.Get("b"), 1)
+	assertNear(t, d.Get("a"), 0)
+	if math.IsInf(d.Total(), 0) || math.IsNaN(d.Total()) {
+		t.Fatalf("total overflowed: %v", d.Total())
+	}
+}
@@ -0,0 +1,202 @@
+package counter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ChangeKind classifies a DiffEntry.
+type ChangeKind int
+
+const (
+	// Added means the key was missing (or zero) before.
+	Added ChangeKind = iota + 1
+	// Removed means the key is missing (or zero) after.
+	Removed
+	// Changed means the key has different non-zero counts before and after.
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// DiffEntry describes how one key's count moved between two snapshots.
+type DiffEntry[T comparable] struct {
+	Key           T
+	Kind          ChangeKind
+	Before, After int64
+	Delta         int64 // After - Before
+}
+
+// Relative returns Delta relative to Before, e.g. 0.2 for +20%.
+// It is +Inf or -Inf for added keys.
+func (e DiffEntry[T]) Relative() float64 {
+	if e.Before == 0 {
+		return math.Inf(sign(e.Delta))
+	}
+	return float64(e.Delta) / math.Abs(float64(e.Before))
+}
+
+func sign(n int64) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+
+// DiffReport groups the differences between two counters. Each list is
+// sorted by magnitude: |Delta| descending, then by key.
+type DiffReport[T comparable] struct {
+	Added, Removed, Changed []DiffEntry[T]
+}
+
+// Diff compares snapshot a (before) with b (after). Missing keys count as
+// zero, so a key stored with count 0 on one side and missing on the other is
+// unchanged.
+func Diff[T comparable](a, b Counter[T]) DiffReport[T] {
+	var r DiffReport[T]
+	add := func(k T, before, after int64) {
+		e := DiffEntry[T]{Key: k, Before: before, After: after, Delta: after - before}
+		switch {
+		case before == after:
+			return
+		case before == 0:
+			e.Kind = Added
+			r.Added = append(r.Added, e)
+		case after == 0:
+			e.Kind = Removed
+			r.Removed = append(r.Removed, e)
+		default:
+			e.Kind = Changed
+			r.Changed = append(r.Changed, e)
+		}
+	}
+	for k, v := range a {
+		add(k, v, b[k])
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok {
+			add(k, 0, v)
+		}
+	}
+	byMag := byMagnitude[T]()
+	slices.SortFunc(r.Added, byMag)
+	slices.SortFunc(r.Removed, byMag)
+	slices.SortFunc(r.Changed, byMag)
+	return r
+}
+
+func byMagnitude[T comparable]() func(a, b DiffEntry[T]) int {
+	keyCmp := defaultCompare[T]()
+	abs := func(n int64) uint64 {
+		if n < 0 {
+			return uint64(-n)
+		}
+		return uint64(n)
+	}
+	return func(a, b DiffEntry[T]) int {
+		if da, db := abs(a.Delta), abs(b.Delta); da != db {
+			if da > db {
+				return -1
+			}
+			return 1
+		}
+		return keyCmp(a.Key, b.Key)
+	}
+}
+
+// Empty reports whether the two snapshots were equal.
+func (r DiffReport[T]) Empty() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Changed) == 0
+}
+
+// ByMagnitude returns every entry in one list sorted by |Delta| descending.
+func (r DiffReport[T]) ByMagnitude() []DiffEntry[T] {
+	all := slices.Concat(r.Added, r.Removed, r.Changed)
+	slices.SortFunc(all, byMagnitude[T]())
+	return all
+}
+
+// WriteText renders the report for humans, one line per key:
+//
+//	before := Counter[string]{"gone": 3, "busy": 10}
+//	after := Counter[string]{"new": 5, "busy": 12}
+//	Diff(before, after).WriteText(os.Stdout)
+//	// Output:
+//	// + new: 0 -> 5 (+5)
+//	// - gone: 3 -> 0 (-3)
+//	// ~ busy: 10 -> 12 (+2, +20.0%)
+func (r DiffReport[T]) WriteText(w io.Writer) error {
+	if r.Empty() {
+		_, err := io.WriteString(w, "no changes\n")
+		return err
+	}
+	var b strings.Builder
+	for _, group := range []struct {
+		mark    string
+		entries []DiffEntry[T]
+	}{{"+", r.Added}, {"-", r.Removed}, {"~", r.Changed}} {
+		for _, e := range group.entries {
+			fmt.Fprintf(&b, "%s %v: %d -> %d (%+d", group.mark, e.Key, e.Before, e.After, e.Delta)
+			if e.Kind == Changed {
+				fmt.Fprintf(&b, ", %+.1f%%", 100*e.Relative())
+			}
+			b.WriteString(")\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String returns the WriteText rendering.
+func (r DiffReport[T]) String() string {
+	var b bytes.Buffer
+	r.WriteText(&b)
+	return b.String()
+}
+
+// MarshalJSON encodes the report as
+//
+//	{"added":[...],"removed":[...],"changed":[...]}
+//
+// where each entry is {"key":…,"before":…,"after":…,"delta":…,"relative":…}.
+// "relative" is omitted for added keys, since it is infinite.
+func (r DiffReport[T]) MarshalJSON() ([]byte, error) {
+	type entry struct {
+		Key      T        `json:"key"`
+		Before   int64    `json:"before"`
+		After    int64    `json:"after"`
+		Delta    int64    `json:"delta"`
+		Relative *float64 `json:"relative,omitempty"`
+	}
+	conv := func(es []DiffEntry[T]) []entry {
+		out := make([]entry, len(es))
+		for i, e := range es {
+			out[i] = entry{Key: e.Key, Before: e.Before, After: e.After, Delta: e.Delta}
+			if e.Before != 0 {
+				rel := e.Relative()
+				out[i].Relative = &rel
+			}
+		}
+		return out
+	}
+	return json.Marshal(struct {
+		Added   []entry `json:"added"`
+		Removed []entry `json:"removed"`
+		Changed []entry `json:"changed"`
+	}{conv(r.Added), conv(r.Removed), conv(r.Changed)})
+}
@@ -0,0 +1,60 @@
+package counter_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestDiff(t *testing.T) {
+	before := counter.Counter[string]{"same": 4, "busy": 10, "gone": 3, "calm": 8, "ghost": 0}
+	after := counter.Counter[string]{"same": 4, "busy": 12, "calm": 2, "new": 5, "new2": 5}
+
+	r := counter.Diff(before, after)
+	assertEqual(t, r.Empty(), false)
+	assertSliceEq(t, r.Added, []counter.DiffEntry[string]{
+		{Key: "new", Kind: counter.Added, Before: 0, After: 5, Delta: 5},
+		{Key: "new2", Kind: counter.Added, Before: 0, After: 5, Delta: 5},
+	})
+	assertSliceEq(t, r.Removed, []counter.DiffEntry[string]{
+		{Key: "gone", Kind: counter.Removed, Before: 3, After: 0, Delta: -3},
+	})
+	assertSliceEq(t, r.Changed, []counter.DiffEntry[string]{
+		{Key: "calm", Kind: counter.Changed, Before: 8, After: 2, Delta: -6},
+		{Key: "busy", Kind: counter.Changed, Before: 10, After: 12, Delta: 2},
+	})
+	assertEqual(t, r.Changed[1].Relative(), 0.2)
+	assertEqual(t, math.IsInf(r.Added[0].Relative(), 1), true)
+
+	all := r.ByMagnitude()
+	assertEqual(t, len(all), 5)
+	assertEqual(t, all[0].Key, "calm")
+	assertEqual(t, all[len(all)-1].Key, "busy")
+
+	const wantText = `+ new: 0 -> 5 (+5)
++ new2: 0 -> 5 (+5)
+- gone: 3 -> 0 (-3)
+~ calm: 8 -> 2 (-6, -75.0%)
+~ busy: 10 -> 12 (+2, +20.0%)
+`
+	assertEqual(t, r.String(), wantText)
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const wantJSON = `{"added":[{"key":"new","before":0,"after":5,"delta":5},{"key":"new2","before":0,"after":5,"delta":5}],` +
+		`"removed":[{"key":"gone","before":3,"after":0,"delta":-3,"relative":-1}],` +
+		`"changed":[{"key":"calm","before":8,"after":2,"delta":-6,"relative":-0.75},{"key":"busy","before":10,"after":12,"delta":2,"relative":0.2}]}`
+	assertEqual(t, string(data), wantJSON)
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	r := counter.Diff(counter.Counter[int]{1: 2, 3: 0}, counter.Counter[int]{1: 2})
+	assertEqual(t, r.Empty(), true)
+	assertEqual(t, r.String(), "no changes\n")
+	data, _ := json.Marshal(r)
+	assertEqual(t, string(data), `{"added":[],"removed":[],"changed":[]}`)
+}
@@ -0,0 +1,499 @@
+// Package durable persists a counter.SafeCounter with a write-ahead log.
+//
+// Every mutation is appended to a log file as a checksummed record before it
+// is applied in memory. Periodically the whole state is written to a
+// snapshot and a fresh log is started, which keeps recovery time bounded.
+//
+// On-disk layout inside the directory, for generation g:
+//
+//	snapshot-g.json  state at the start of generation g (absent for g = 0)
+//	wal-g.log        mutations made during generation g
+//
+// Open loads the newest snapshot and replays its log. A torn record at the
+// end of the log, which is what a crash in the middle of an append leaves
+// behind, is discarded. Anything else Open reports as ErrCorrupt and leaves
+// the log untouched: a damaged record followed by intact ones, or an intact
+// record that does not decode (for example, opened with another key type).
+package durable
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// DefaultSnapshotEvery is the number of log records after which a snapshot
+// is written when Options.SnapshotEvery is zero.
+const DefaultSnapshotEvery = 10000
+
+// Options tune a durable Counter.
+type Options struct {
+	// SnapshotEvery is the number of log records between automatic
+	// snapshots; 0 means DefaultSnapshotEvery and a negative value disables
+	// automatic snapshots.
+	SnapshotEvery int
+	// Sync fsyncs the log after every record. Without it a record survives
+	// a process crash but may be lost if the machine loses power.
+	Sync bool
+}
+
+// Counter is a SafeCounter whose mutations are logged to disk.
+// Read methods do not touch the disk. Counter is safe for concurrent use.
+type Counter[T comparable] struct {
+	mu      sync.Mutex // orders log appends with in-memory applies
+	sc      *counter.SafeCounter[T]
+	dir     string
+	opts    Options
+	gen     uint64
+	log     logFile
+	size    int64 // bytes of intact records in the current log
+	records int   // records in the current log
+	snapAt  int   // records that trigger the next automatic snapshot
+	snapErr error // result of the last automatic snapshot
+	closed  bool
+	broken  error // set when the log could not be restored after a failed append
+}
+
+// logFile is the part of *os.File the log needs.
+type logFile interface {
+	io.Writer
+	Sync() error
+	Truncate(size int64) error
+	Seek(offset int64, whence int) (int64, error)
+	Close() error
+}
+
+var (
+	// ErrClosed is returned by mutations after Close.
+	ErrClosed = errors.New("durable: counter is closed")
+	// ErrBroken is returned by mutations after an append failed and the
+	// partial record could not be removed from the log. Snapshot or reopen
+	// the counter to recover.
+	ErrBroken = errors.New("durable: log is unusable")
+	// ErrCorrupt is returned by Open when a damaged log record is followed
+	// by intact ones, or an intact record cannot be decoded.
+	ErrCorrupt = errors.New("durable: corrupt log")
+)
+
+// Record operations.
+const (
+	opAddN   byte = 1
+	opSet    byte = 2
+	opDelete byte = 3
+)
+
+// recordHeader is a little-endian payload length followed by its CRC-32C.
+const recordHeader = 8
+
+// maxRecord bounds the payload of a log record, which keeps the search for
+// intact records after a damaged one cheap.
+const maxRecord = 1 << 20
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Open loads or creates a durable counter in dir.
+func Open[T comparable](dir string, opts Options) (*Counter[T], error) {
+	if opts.SnapshotEvery == 0 {
+		opts.SnapshotEvery = DefaultSnapshotEvery
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("durable: %w", err)
+	}
+	c := &Counter[T]{sc: counter.NewSafe[T](), dir: dir, opts: opts, snapAt: opts.SnapshotEvery}
+
+	gen, err := latestSnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+	c.gen = gen
+	if gen > 0 {
+		if err := c.loadSnapshot(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.replay(); err != nil {
+		return nil, err
+	}
+	c.removeStale()
+	return c, nil
+}
+
+func (c *Counter[T]) snapshotPath(gen uint64) string {
+	return filepath.Join(c.dir, fmt.Sprintf("snapshot-%d.json", gen))
+}
+
+func (c *Counter[T]) logPath(gen uint64) string {
+	return filepath.Join(c.dir, fmt.Sprintf("wal-%d.log", gen))
+}
+
+// latestSnapshot returns the highest snapshot generation in dir (0 if none).
+func latestSnapshot(dir string) (uint64, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "snapshot-*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("durable: %w", err)
+	}
+	var gens []uint64
+	for _, name := range names {
+		s := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), "snapshot-"), ".json")
+		if g, err := strconv.ParseUint(s, 10, 64); err == nil {
+			gens = append(gens, g)
+		}
+	}
+	if len(gens) == 0 {
+		return 0, nil
+	}
+	sort.Slice(gens, func(i, j int) bool { return gens[i] < gens[j] })
+	return gens[len(gens)-1], nil
+}
+
+// loadSnapshot reads snapshot-<gen>.json: a record header followed by the
+// JSON encoding of the counter.
+func (c *Counter[T]) loadSnapshot() error {
+	data, err := os.ReadFile(c.snapshotPath(c.gen))
+	if err != nil {
+		return fmt.Errorf("durable: %w", err)
+	}
+	payload, n := decodeRecord(data)
+	if n != len(data) {
+		return fmt.Errorf("durable: corrupt snapshot %s", c.snapshotPath(c.gen))
+	}
+	var snap counter.Counter[T]
+	if err := json.Unmarshal(payload, &snap); err != nil {
+		return fmt.Errorf("durable: snapshot %s: %w", c.snapshotPath(c.gen), err)
+	}
+	c.sc.Update(snap)
+	return nil
+}
+
+// replay applies the current generation's log and leaves it open for
+// appending, truncated after the last intact record.
+func (c *Counter[T]) replay() error {
+	f, err := os.OpenFile(c.logPath(c.gen), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("durable: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("durable: %w", err)
+	}
+	off := 0
+	for off < len(data) {
+		payload, n := decodeRecord(data[off:])
+		if n == 0 {
+			break // checksum failed: torn, unless intact records follow
+		}
+		if err := c.apply(payload); err != nil {
+			// The record is exactly what was written, so it is not torn:
+			// the key type or the format differs from this program's.
+			f.Close()
+			return fmt.Errorf("%w: %s: record at offset %d: %v", ErrCorrupt, c.logPath(c.gen), off, err)
+		}
+		off += n
+		c.records++
+	}
+	if off < len(data) {
+		if next := intactAfter(data, off); next >= 0 {
+			f.Close()
+			return fmt.Errorf("%w: %s: damaged record at offset %d followed by an intact one at %d",
+				ErrCorrupt, c.logPath(c.gen), off, next)
+		}
+		if err := f.Truncate(int64(off)); err != nil {
+			f.Close()
+			return fmt.Errorf("durable: truncate torn log: %w", err)
+		}
+	}
+	if _, err := f.Seek(int64(off), io.SeekStart); err != nil {
+		f.Close()
+		return fmt.Errorf("durable: %w", err)
+	}
+	c.log, c.size = f, int64(off)
+	return nil
+}
+
+// intactAfter returns the offset of the first record with a valid checksum
+// that starts after the damaged one at off, or -1 if the rest of data is a
+// torn tail. Whether the payload decodes does not matter here.
+func intactAfter(data []byte, off int) int {
+	for p := off + 1; p+recordHeader < len(data); p++ {
+		size := int(binary.LittleEndian.Uint32(data[p:]))
+		if size < 1 || size > maxRecord || size > len(data)-p-recordHeader {
+			continue
+		}
+		if _, n := decodeRecord(data[p:]); n > 0 {
+			return p
+		}
+	}
+	return -1
+}
+
+// decodePayload splits a log payload into its operation, key and count.
+func decodePayload[T comparable](payload []byte) (op byte, k T, n int64, err error) {
+	if len(payload) < 1 {
+		return 0, k, 0, errors.New("empty record")
+	}
+	op = payload[0]
+	if op < opAddN || op > opDelete {
+		return 0, k, 0, fmt.Errorf("unknown op %d", op)
+	}
+	n, w := binary.Varint(payload[1:])
+	if w <= 0 {
+		return 0, k, 0, errors.New("bad count")
+	}
+	if err := json.Unmarshal(payload[1+w:], &k); err != nil {
+		return 0, k, 0, err
+	}
+	return op, k, n, nil
+}
+
+// apply decodes one log payload and applies it to the in-memory counter.
+func (c *Counter[T]) apply(payload []byte) error {
+	op, k, n, err := decodePayload[T](payload)
+	if err != nil {
+		return err
+	}
+	c.applyOp(op, k, n)
+	return nil
+}
+
+// applyOp applies one decoded mutation to the in-memory counter.
+func (c *Counter[T]) applyOp(op byte, k T, n int64) {
+	switch op {
+	case opAddN:
+		c.sc.AddN(k, n)
+	case opSet:
+		c.sc.Set(k, n)
+	case opDelete:
+		c.sc.Delete(k)
+	}
+}
+
+// encodeRecord frames payload with its length and checksum.
+func encodeRecord(payload []byte) []byte {
+	out := make([]byte, recordHeader, recordHeader+len(payload))
+	binary.LittleEndian.PutUint32(out[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(out[4:8], crc32.Checksum(payload, crcTable))
+	return append(out, payload...)
+}
+
+// decodeRecord returns the first record's payload and its framed size, or
+// n == 0 if data does not start with an intact record.
+func decodeRecord(data []byte) (payload []byte, n int) {
+	if len(data) < recordHeader {
+		return nil, 0
+	}
+	size := int(binary.LittleEndian.Uint32(data[0:4]))
+	if size > len(data)-recordHeader {
+		return nil, 0
+	}
+	payload = data[recordHeader : recordHeader+size]
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(data[4:8]) {
+		return nil, 0
+	}
+	return payload, recordHeader + size
+}
+
+// write logs one mutation, applies it and snapshots if due.
+func (c *Counter[T]) write(op byte, k T, n int64) error {
+	key, err := json.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("durable: encode key: %w", err)
+	}
+	payload := append([]byte{op}, binary.AppendVarint(nil, n)...)
+	payload = append(payload, key...)
+	if len(payload) > maxRecord {
+		return fmt.Errorf("durable: key %v encodes to more than %d bytes", k, maxRecord)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	if c.broken != nil {
+		return c.broken
+	}
+	rec := encodeRecord(payload)
+	if _, err := c.log.Write(rec); err != nil {
+		return c.rollback(fmt.Errorf("durable: append: %w", err))
+	}
+	c.size += int64(len(rec))
+	// The record is in the log and will be replayed after a restart, so it
+	// is applied now whatever happens below.
+	c.applyOp(op, k, n)
+	c.records++
+
+	var syncErr error
+	if c.opts.Sync {
+		if err := c.log.Sync(); err != nil {
+			syncErr = fmt.Errorf("durable: sync (mutation applied): %w", err)
+		}
+	}
+	if c.opts.SnapshotEvery > 0 && c.records >= c.snapAt {
+		c.snapErr = c.snapshot()
+		if c.snapErr != nil {
+			c.snapAt = c.records + c.opts.SnapshotEvery // retry later
+		}
+	}
+	return syncErr
+}
+
+// SnapshotErr returns the error of the last automatic snapshot, or nil if it
+// succeeded. A failed automatic snapshot does not fail the mutation that
+// triggered it; it is retried after another Options.SnapshotEvery records.
+func (c *Counter[T]) SnapshotErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.snapErr
+}
+
+// rollback removes whatever part of a failed append reached the log, so the
+// next record starts right after the last intact one. If that fails too the
+// counter refuses further writes: appending after torn bytes would hide the
+// new records from replay. Caller holds mu.
+func (c *Counter[T]) rollback(err error) error {
+	if terr := c.log.Truncate(c.size); terr != nil {
+		c.broken = fmt.Errorf("%w: %v (truncate: %v)", ErrBroken, err, terr)
+		return c.broken
+	}
+	if _, serr := c.log.Seek(c.size, io.SeekStart); serr != nil {
+		c.broken = fmt.Errorf("%w: %v (seek: %v)", ErrBroken, err, serr)
+		return c.broken
+	}
+	return err
+}
+
+// Add increments key by 1.
+func (c *Counter[T]) Add(k T) error { return c.write(opAddN, k, 1) }
+
+// AddN increments key by n (n can be negative).
+func (c *Counter[T]) AddN(k T, n int64) error { return c.write(opAddN, k, n) }
+
+// Set sets the count for key.
+func (c *Counter[T]) Set(k T, n int64) error { return c.write(opSet, k, n) }
+
+// Delete removes a key.
+func (c *Counter[T]) Delete(k T) error { return c.write(opDelete, k, 0) }
+
+// Get returns count for key (0 if missing).
+func (c *Counter[T]) Get(k T) int64 { return c.sc.Get(k) }
+
+// Total returns the sum of all positive counts.
+func (c *Counter[T]) Total() int64 { return c.sc.Total() }
+
+// Items returns all (key, count) pairs (unsorted).
+func (c *Counter[T]) Items() []counter.Pair[T] { return c.sc.Items() }
+
+// MostCommon returns top-n pairs, see counter.Counter.MostCommon.
+func (c *Counter[T]) MostCommon(n int) []counter.Pair[T] { return c.sc.MostCommon(n) }
+
+// Clone returns a copy of the current state.
+func (c *Counter[T]) Clone() counter.Counter[T] { return c.sc.Clone() }
+
+// Snapshot writes the current state to a new snapshot and starts a new log.
+func (c *Counter[T]) Snapshot() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	return c.snapshot()
+}
+
+// snapshot moves to the next generation. The new snapshot becomes visible
+// atomically via rename; until then a crash recovers from the old one.
+// Caller holds mu.
+func (c *Counter[T]) snapshot() error {
+	data, err := json.Marshal(c.sc.Clone())
+	if err != nil {
+		return fmt.Errorf("durable: encode snapshot: %w", err)
+	}
+	next := c.gen + 1
+	tmp := c.snapshotPath(next) + ".tmp"
+	if err := writeFileSync(tmp, encodeRecord(data)); err != nil {
+		return err
+	}
+	// Create the new log before publishing the snapshot, so a published
+	// snapshot always has a log to append to.
+	log, err := os.OpenFile(c.logPath(next), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("durable: %w", err)
+	}
+	if err := os.Rename(tmp, c.snapshotPath(next)); err != nil {
+		log.Close()
+		return fmt.Errorf("durable: publish snapshot: %w", err)
+	}
+	syncDir(c.dir)
+
+	c.log.Close()
+	c.log, c.gen, c.size, c.records = log, next, 0, 0
+	c.broken = nil // the new log starts clean
+	c.snapAt = c.opts.SnapshotEvery
+	c.removeStale()
+	return nil
+}
+
+// removeStale deletes files of older generations; failures are harmless.
+func (c *Counter[T]) removeStale() {
+	for g := c.gen; g > 0; g-- {
+		snapErr := os.Remove(c.snapshotPath(g - 1))
+		logErr := os.Remove(c.logPath(g - 1))
+		if os.IsNotExist(snapErr) && os.IsNotExist(logErr) {
+			break
+		}
+	}
+	stray, _ := filepath.Glob(filepath.Join(c.dir, "snapshot-*.json.tmp"))
+	for _, name := range stray {
+		os.Remove(name)
+	}
+}
+
+// Close flushes the log to disk and releases it.
+func (c *Counter[T]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	err := c.log.Sync()
+	if cerr := c.log.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func writeFileSync(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("durable: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("durable: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("durable: %w", err)
+	}
+	return f.Close()
+}
+
+// syncDir makes renames in dir durable where the platform supports it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
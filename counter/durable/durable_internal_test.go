@@ -0,0 +1,202 @@
+package durable
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// faultyLog wraps the real log and makes chosen calls fail. A failing Write
+// still writes half of its input, like a disk that fills up mid-record.
+type faultyLog struct {
+	logFile
+	failWrite, failTruncate, failSync bool
+}
+
+func (f *faultyLog) Write(p []byte) (int, error) {
+	if f.failWrite {
+		n, _ := f.logFile.Write(p[:len(p)/2])
+		return n, syscall.ENOSPC
+	}
+	return f.logFile.Write(p)
+}
+
+func (f *faultyLog) Truncate(size int64) error {
+	if f.failTruncate {
+		return syscall.EIO
+	}
+	return f.logFile.Truncate(size)
+}
+
+func (f *faultyLog) Sync() error {
+	if f.failSync {
+		return syscall.EIO
+	}
+	return f.logFile.Sync()
+}
+
+func inject(t *testing.T, c *Counter[string]) *faultyLog {
+	t.Helper()
+	f := &faultyLog{logFile: c.log}
+	c.log = f
+	return f
+}
+
+func TestFailedAppendIsRolledBack(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[string](dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Add("before")
+	f := inject(t, c)
+
+	f.failWrite = true
+	if err := c.Add("lost"); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("want ENOSPC, got %v", err)
+	}
+	f.failWrite = false
+	// 失败之后确认的写入必须在重启后仍然存在
+	if err := c.Add("after"); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	re, err := Open[string](dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer re.Close()
+	if re.Get("before") != 1 || re.Get("lost") != 0 || re.Get("after") != 1 {
+		t.Fatalf("recovered %v", re.Clone())
+	}
+}
+
+func TestUnrecoverableAppendBreaksCounter(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[string](dir, Options{SnapshotEvery: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.Add("a")
+	f := inject(t, c)
+
+	f.failWrite, f.failTruncate = true, true
+	if err := c.Add("x"); !errors.Is(err, ErrBroken) {
+		t.Fatalf("want ErrBroken, got %v", err)
+	}
+	f.failWrite, f.failTruncate = false, false
+	if err := c.Add("y"); !errors.Is(err, ErrBroken) {
+		t.Fatalf("writes after a broken append: want ErrBroken, got %v", err)
+	}
+
+	// 快照换到新日志后恢复可写
+	if err := c.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add("z"); err != nil {
+		t.Fatal(err)
+	}
+	re, err := Open[string](dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer re.Close()
+	if re.Get("a") != 1 || re.Get("x") != 0 || re.Get("z") != 1 {
+		t.Fatalf("recovered %v", re.Clone())
+	}
+}
+
+func TestSyncFailureStillApplies(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[string](dir, Options{Sync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := inject(t, c)
+	f.failSync = true
+	if err := c.Add("a"); !errors.Is(err, syscall.EIO) {
+		t.Fatalf("want EIO, got %v", err)
+	}
+	// 记录已在日志中：内存必须与重启后一致
+	if c.Get("a") != 1 {
+		t.Fatalf("mutation not applied after sync failure")
+	}
+	f.failSync = false
+	c.Close()
+
+	re, err := Open[string](dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer re.Close()
+	if re.Get("a") != 1 {
+		t.Fatalf("recovered %v", re.Clone())
+	}
+}
+
+func TestAutoSnapshotFailureDoesNotFailWrites(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[string](dir, Options{SnapshotEvery: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// 用同名目录挡住快照临时文件，使自动快照失败
+	block := filepath.Join(dir, "snapshot-1.json.tmp")
+	if err := os.Mkdir(block, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := c.Add("k"); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+	if c.SnapshotErr() == nil {
+		t.Fatal("want the automatic snapshot error to be recorded")
+	}
+	if c.gen != 0 {
+		t.Fatalf("generation %d, want 0", c.gen)
+	}
+
+	// 障碍移除后，下一次到期时重试成功
+	os.Remove(block)
+	c.Add("k")
+	c.Add("k")
+	if err := c.SnapshotErr(); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if c.gen != 1 || c.Get("k") != 7 {
+		t.Fatalf("gen %d k=%d", c.gen, c.Get("k"))
+	}
+}
+
+func TestUnknownOpIsNotTruncated(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[string](dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Add("a")
+	c.Close()
+
+	// 校验和正确的未知操作码（例如新版本写入的记录）位于日志末尾
+	wal := filepath.Join(dir, "wal-0.log")
+	f, err := os.OpenFile(wal, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write(encodeRecord([]byte{9, 2, '"', 'b', '"'}))
+	f.Close()
+	before, _ := os.ReadFile(wal)
+
+	if _, err := Open[string](dir, Options{}); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("want ErrCorrupt, got %v", err)
+	}
+	if after, _ := os.ReadFile(wal); len(after) != len(before) {
+		t.Fatalf("log changed: %d -> %d bytes", len(before), len(after))
+	}
+}
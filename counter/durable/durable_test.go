@@ -0,0 +1,270 @@
+package durable_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/durable"
+)
+
+func open[T comparable](t *testing.T, dir string, opts durable.Options) *durable.Counter[T] {
+	t.Helper()
+	c, err := durable.Open[T](dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func assertState[T comparable](t *testing.T, c *durable.Counter[T], want counter.Counter[T]) {
+	t.Helper()
+	if got := c.Clone(); !got.Equal(want) || len(got) != len(want) {
+		t.Fatalf("state = %v, want %v", got, want)
+	}
+}
+
+func TestReopenAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	c := open[string](t, dir, durable.Options{Sync: true})
+	c.Add("a")
+	c.AddN("a", 4)
+	c.Set("b", 7)
+	c.AddN("c", -2)
+	c.Add("gone")
+	c.Delete("gone")
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add("a"); err != durable.ErrClosed {
+		t.Fatalf("Add after Close: %v", err)
+	}
+
+	c = open[string](t, dir, durable.Options{})
+	defer c.Close()
+	assertState(t, c, counter.Counter[string]{"a": 5, "b": 7, "c": -2})
+}
+
+func TestRecoverWithoutClose(t *testing.T) {
+	dir := t.TempDir()
+	c := open[int](t, dir, durable.Options{})
+	for i := 0; i < 100; i++ {
+		c.Add(i % 7)
+	}
+	// 模拟进程崩溃：不调用 Close，直接重新打开
+	re := open[int](t, dir, durable.Options{})
+	defer re.Close()
+	assertEqual(t, re.Total(), int64(100))
+	assertEqual(t, re.Get(0), int64(15))
+}
+
+func TestTornTail(t *testing.T) {
+	for name, garbage := range map[string][]byte{
+		"short header": {0x05, 0x00},
+		"short body":   {0x20, 0, 0, 0, 1, 2, 3, 4, 1, 2},
+		"bad checksum": {0x02, 0, 0, 0, 0xde, 0xad, 0xbe, 0xef, 1, 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			c := open[string](t, dir, durable.Options{})
+			c.AddN("x", 3)
+			c.Add("y")
+			c.Close()
+
+			wal := filepath.Join(dir, "wal-0.log")
+			intact, _ := os.Stat(wal)
+			f, err := os.OpenFile(wal, os.O_APPEND|os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write(garbage)
+			f.Close()
+
+			c = open[string](t, dir, durable.Options{})
+			assertState(t, c, counter.Counter[string]{"x": 3, "y": 1})
+			// 截断后的日志可以继续追加
+			if fi, _ := os.Stat(wal); fi.Size() != intact.Size() {
+				t.Fatalf("log size %d, want truncated to %d", fi.Size(), intact.Size())
+			}
+			c.Add("z")
+			c.Close()
+
+			c = open[string](t, dir, durable.Options{})
+			defer c.Close()
+			assertState(t, c, counter.Counter[string]{"x": 3, "y": 1, "z": 1})
+		})
+	}
+}
+
+func TestTornRecordMidLog(t *testing.T) {
+	dir := t.TempDir()
+	c := open[string](t, dir, durable.Options{})
+	c.Add("a")
+	c.Add("b")
+	c.Add("c")
+	c.Close()
+
+	// 截掉最后一条记录的一半：相当于写到一半掉电
+	wal := filepath.Join(dir, "wal-0.log")
+	data, _ := os.ReadFile(wal)
+	os.WriteFile(wal, data[:len(data)-3], 0o644)
+
+	c = open[string](t, dir, durable.Options{})
+	defer c.Close()
+	assertState(t, c, counter.Counter[string]{"a": 1, "b": 1})
+}
+
+func TestSnapshotCompaction(t *testing.T) {
+	dir := t.TempDir()
+	c := open[string](t, dir, durable.Options{SnapshotEvery: 5})
+	for i := 0; i < 12; i++ {
+		c.Add([]string{"a", "b", "c"}[i%3])
+	}
+	c.Close()
+
+	names := func() []string {
+		entries, _ := os.ReadDir(dir)
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Name())
+		}
+		return out
+	}
+	// 12 条记录 -> 两次快照，旧代的文件已删除
+	if got, want := names(), []string{"snapshot-2.json", "wal-2.log"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	c = open[string](t, dir, durable.Options{SnapshotEvery: -1})
+	defer c.Close()
+	assertState(t, c, counter.Counter[string]{"a": 4, "b": 4, "c": 4})
+	if err := c.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	c.Add("a")
+	re := open[string](t, dir, durable.Options{})
+	defer re.Close()
+	assertState(t, re, counter.Counter[string]{"a": 5, "b": 4, "c": 4})
+}
+
+func TestCrashDuringSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	c := open[string](t, dir, durable.Options{SnapshotEvery: -1})
+	c.AddN("a", 2)
+	c.Snapshot()
+	c.AddN("a", 3)
+	c.Close()
+
+	// 崩溃在快照发布之前：留下半写的临时文件和空的新日志，应被忽略
+	os.WriteFile(filepath.Join(dir, "snapshot-2.json.tmp"), []byte("partial"), 0o644)
+	os.WriteFile(filepath.Join(dir, "wal-2.log"), nil, 0o644)
+	// 崩溃在删除旧文件之前：旧代的日志不能被重放
+	os.WriteFile(filepath.Join(dir, "wal-0.log"), []byte("stale"), 0o644)
+
+	c = open[string](t, dir, durable.Options{})
+	defer c.Close()
+	assertState(t, c, counter.Counter[string]{"a": 5})
+	if _, err := os.Stat(filepath.Join(dir, "snapshot-2.json.tmp")); !os.IsNotExist(err) {
+		t.Fatalf("stray temp snapshot not removed: %v", err)
+	}
+}
+
+func TestCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	c := open[int](t, dir, durable.Options{})
+	c.Add(1)
+	c.Snapshot()
+	c.Close()
+
+	snap := filepath.Join(dir, "snapshot-1.json")
+	data, _ := os.ReadFile(snap)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(snap, data, 0o644)
+
+	if _, err := durable.Open[int](dir, durable.Options{}); err == nil {
+		t.Fatal("want error for corrupt snapshot")
+	}
+}
+
+func TestStructKeys(t *testing.T) {
+	type edge struct{ From, To string }
+	dir := t.TempDir()
+	c := open[edge](t, dir, durable.Options{SnapshotEvery: 2})
+	c.Add(edge{"a", "b"})
+	c.Add(edge{"a", "b"})
+	c.Add(edge{"b", "c"})
+	c.Close()
+
+	c = open[edge](t, dir, durable.Options{})
+	defer c.Close()
+	assertState(t, c, counter.Counter[edge]{{"a", "b"}: 2, {"b", "c"}: 1})
+}
+
+func TestDamagedRecordBeforeIntactOnes(t *testing.T) {
+	build := func(t *testing.T) (dir string, wal string, data []byte) {
+		dir = t.TempDir()
+		c := open[string](t, dir, durable.Options{})
+		c.Add("a")
+		c.Add("b")
+		c.Add("c")
+		c.Close()
+		wal = filepath.Join(dir, "wal-0.log")
+		data, _ = os.ReadFile(wal)
+		return dir, wal, data
+	}
+
+	t.Run("bit flip", func(t *testing.T) {
+		dir, wal, data := build(t)
+		data[10] ^= 0xff // 第一条记录的负载
+		os.WriteFile(wal, data, 0o644)
+		if _, err := durable.Open[string](dir, durable.Options{}); !errors.Is(err, durable.ErrCorrupt) {
+			t.Fatalf("want ErrCorrupt, got %v", err)
+		}
+		// 好数据不能被截掉
+		if fi, _ := os.Stat(wal); fi.Size() != int64(len(data)) {
+			t.Fatalf("log truncated to %d bytes", fi.Size())
+		}
+	})
+
+	t.Run("torn record mid-log", func(t *testing.T) {
+		dir, wal, data := build(t)
+		first := len(data) / 3
+		torn := append(append(append([]byte{}, data[:first]...), data[first:first+5]...), data[first:]...)
+		os.WriteFile(wal, torn, 0o644)
+		if _, err := durable.Open[string](dir, durable.Options{}); !errors.Is(err, durable.ErrCorrupt) {
+			t.Fatalf("want ErrCorrupt, got %v", err)
+		}
+	})
+}
+
+func assertEqual[T comparable](t *testing.T, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestUndecodableRecordIsNotTruncated(t *testing.T) {
+	dir := t.TempDir()
+	c := open[string](t, dir, durable.Options{})
+	c.Add("a")
+	c.Add("b")
+	c.Close()
+	wal := filepath.Join(dir, "wal-0.log")
+	before, _ := os.ReadFile(wal)
+
+	// 校验和正确但键类型不符：不是写坏的尾部，不能截断
+	if _, err := durable.Open[int](dir, durable.Options{}); !errors.Is(err, durable.ErrCorrupt) {
+		t.Fatalf("want ErrCorrupt, got %v", err)
+	}
+	if after, _ := os.ReadFile(wal); !reflect.DeepEqual(after, before) {
+		t.Fatalf("log changed: %d -> %d bytes", len(before), len(after))
+	}
+
+	c = open[string](t, dir, durable.Options{})
+	defer c.Close()
+	assertState(t, c, counter.Counter[string]{"a": 1, "b": 1})
+}
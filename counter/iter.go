@@ -0,0 +1,102 @@
+package counter
+
+import (
+	"iter"
+	"slices"
+)
+
+// Range-over-func iterators. Unlike Keys, Items and Elements they do not
+// build a result slice up front, e.g.
+//
+//	for k, n := range c.All() { ... }
+
+// All yields every (key, count) pair in map order.
+func (c Counter[T]) All() iter.Seq2[T, int64] {
+	return func(yield func(T, int64) bool) {
+		for k, v := range c {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSorted yields the keys in ascending key order (natural order for
+// built-in ordered types, fmt.Sprint order otherwise, as in MostCommon).
+func (c Counter[T]) KeysSorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		keys := c.Keys()
+		slices.SortFunc(keys, defaultCompare[T]())
+		for _, k := range keys {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ElementsSeq is the lazy form of Elements: it yields each key as many
+// times as its count without materialising the repeated elements.
+// Negative or zero counts are ignored.
+func (c Counter[T]) ElementsSeq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for k, v := range c {
+			for i := int64(0); i < v; i++ {
+				if !yield(k) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MostCommonSeq yields the pairs of MostCommon(n) in order.
+func (c Counter[T]) MostCommonSeq(n int) iter.Seq2[T, int64] {
+	return func(yield func(T, int64) bool) {
+		for _, p := range c.MostCommon(n) {
+			if !yield(p.Key, p.Count) {
+				return
+			}
+		}
+	}
+}
+
+//// Thread-safe variant ////////////////////////////////////////////////////////
+
+// The SafeCounter iterators take a snapshot (Clone) when iteration starts
+// and release the lock before yielding, so the loop body may freely call
+// back into the counter.
+
+// All is the snapshot counterpart of Counter.All.
+func (s *SafeCounter[T]) All() iter.Seq2[T, int64] {
+	return func(yield func(T, int64) bool) { s.Clone().All()(yield) }
+}
+
+// KeysSorted is the snapshot counterpart of Counter.KeysSorted.
+func (s *SafeCounter[T]) KeysSorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		keys := s.Keys()
+		slices.SortFunc(keys, defaultCompare[T]())
+		for _, k := range keys {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ElementsSeq is the snapshot counterpart of Counter.ElementsSeq.
+func (s *SafeCounter[T]) ElementsSeq() iter.Seq[T] {
+	return func(yield func(T) bool) { s.Clone().ElementsSeq()(yield) }
+}
+
+// MostCommonSeq is the snapshot counterpart of Counter.MostCommonSeq.
+func (s *SafeCounter[T]) MostCommonSeq(n int) iter.Seq2[T, int64] {
+	return func(yield func(T, int64) bool) {
+		for _, p := range s.MostCommon(n) {
+			if !yield(p.Key, p.Count) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package counter_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func assertSliceEq[T comparable](t *testing.T, got, want []T) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Fatalf("\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	c := counter.Counter[int]{10: 2, 9: 1, 3: -1}
+
+	seen := counter.New[int]()
+	for k, n := range c.All() {
+		seen.Set(k, n)
+	}
+	assertEqual(t, seen.Equal(c), true)
+
+	assertSliceEq(t, slices.Collect(c.KeysSorted()), []int{3, 9, 10})
+
+	elems := slices.Sorted(c.ElementsSeq())
+	assertSliceEq(t, elems, []int{9, 10, 10})
+
+	var top []counter.Pair[int]
+	for k, n := range c.MostCommonSeq(2) {
+		top = append(top, counter.Pair[int]{Key: k, Count: n})
+	}
+	assertSliceEq(t, top, c.MostCommon(2))
+}
+
+func TestElementsSeqEarlyBreak(t *testing.T) {
+	// 巨大计数也不会分配：只取前 3 个
+	c := counter.Counter[string]{"x": 1 << 40}
+	n := 0
+	for range c.ElementsSeq() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	assertEqual(t, n, 3)
+}
+
+func TestSafeCounterIteratorsSnapshot(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	sc.Update(counter.Counter[string]{"a": 2, "b": 1})
+
+	// 迭代期间写入不会死锁，也不会影响本次迭代
+	n := 0
+	for k := range sc.All() {
+		sc.Add(k + "!")
+		n++
+	}
+	assertEqual(t, n, 2)
+	assertEqual(t, sc.Get("a!"), int64(1))
+
+	assertSliceEq(t, slices.Collect(sc.KeysSorted()), []string{"a", "a!", "b", "b!"})
+	for range sc.ElementsSeq() {
+		sc.Add("z")
+	}
+	assertEqual(t, sc.Get("z"), int64(5))
+	for k, v := range sc.MostCommonSeq(1) {
+		assertEqual(t, k, "z")
+		assertEqual(t, v, int64(5))
+	}
+}
@@ -0,0 +1,146 @@
+package counter
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// JSON layout
+//
+// Counters whose keys are strings (any type with string kind) or implement
+// encoding.TextMarshaler encode as an object, the same way encoding/json
+// encodes a map:
+//
+//	{"a":2,"b":1}
+//
+// Every other key type encodes as an array of [key, count] pairs sorted by key:
+//
+//	[[1,3],[2,1]]
+//
+// Both layouts are deterministic, so encoded counters diff cleanly.
+// UnmarshalJSON accepts either layout.
+
+// textKeys reports whether T encodes as a JSON object key.
+func textKeys[T comparable]() bool {
+	if reflect.TypeFor[T]().Kind() == reflect.String {
+		return true
+	}
+	_, ok := any(*new(T)).(encoding.TextMarshaler)
+	return ok
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Counter[T]) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("null"), nil
+	}
+	if textKeys[T]() {
+		// encoding/json sorts object keys for us.
+		return json.Marshal(map[T]int64(c))
+	}
+
+	type entry struct {
+		key  T
+		json []byte
+	}
+	entries := make([]entry, 0, len(c))
+	for k := range c {
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, fmt.Errorf("counter: marshal key %v: %w", k, err)
+		}
+		entries = append(entries, entry{k, kb})
+	}
+	keyCmp := defaultCompare[T]()
+	slices.SortFunc(entries, func(a, b entry) int {
+		if r := keyCmp(a.key, b.key); r != 0 {
+			return r
+		}
+		return bytes.Compare(a.json, b.json)
+	})
+
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "[%s,%d]", e.json, c[e.key])
+	}
+	b.WriteByte(']')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Like decoding into a map,
+// entries are stored into the existing Counter (allocated if nil).
+func (c *Counter[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if *c == nil {
+		*c = New[T]()
+	}
+	if len(data) > 0 && data[0] == '{' {
+		var m map[T]int64
+		if err := json.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("counter: %w", err)
+		}
+		for k, v := range m {
+			(*c)[k] = v
+		}
+		return nil
+	}
+
+	var pairs [][2]json.RawMessage
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return fmt.Errorf("counter: want object or [[key,count],...]: %w", err)
+	}
+	for _, p := range pairs {
+		var (
+			k T
+			n int64
+		)
+		if err := json.Unmarshal(p[0], &k); err != nil {
+			return fmt.Errorf("counter: key %s: %w", p[0], err)
+		}
+		if err := json.Unmarshal(p[1], &n); err != nil {
+			return fmt.Errorf("counter: count for key %s: %w", p[0], err)
+		}
+		(*c)[k] = n
+	}
+	return nil
+}
+
+//// Thread-safe variant ////////////////////////////////////////////////////////
+
+// MarshalJSON encodes a snapshot of the counter, see Counter.MarshalJSON.
+func (s *SafeCounter[T]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.MarshalJSON()
+}
+
+// UnmarshalJSON decodes into the counter, see Counter.UnmarshalJSON.
+// The input is fully decoded before the lock is taken.
+func (s *SafeCounter[T]) UnmarshalJSON(data []byte) error {
+	var in Counter[T]
+	if err := in.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.c == nil {
+		s.c = New[T]()
+	}
+	s.mu.Unlock()
+	s.writeKeys(keysOf(in), func() {
+		for k, v := range in {
+			s.c[k] = v
+		}
+	})
+	return nil
+}
@@ -0,0 +1,110 @@
+package counter_test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestJSONStringKeysAsObject(t *testing.T) {
+	c := counter.Counter[string]{"b": 1, "a": 2, "c": -1}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `{"a":2,"b":1,"c":-1}`)
+
+	var back counter.Counter[string]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, back.Equal(c), true)
+}
+
+func TestJSONTextMarshalerKeysAsObject(t *testing.T) {
+	c := counter.Counter[netip.Addr]{
+		netip.MustParseAddr("10.0.0.2"): 3,
+		netip.MustParseAddr("10.0.0.1"): 1,
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `{"10.0.0.1":1,"10.0.0.2":3}`)
+
+	var back counter.Counter[netip.Addr]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, back.Equal(c), true)
+}
+
+func TestJSONOtherKeysAsSortedPairs(t *testing.T) {
+	c := counter.Counter[int]{10: 1, 9: 2, -3: 4}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `[[-3,4],[9,2],[10,1]]`)
+
+	var back counter.Counter[int]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, back.Equal(c), true)
+
+	type point struct{ X, Y int }
+	p := counter.Counter[point]{{2, 1}: 1, {1, 2}: 5}
+	data, err = json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `[[{"X":1,"Y":2},5],[{"X":2,"Y":1},1]]`)
+	var pb counter.Counter[point]
+	if err := json.Unmarshal(data, &pb); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, pb.Equal(p), true)
+}
+
+func TestJSONNestedAndErrors(t *testing.T) {
+	type report struct {
+		Hits counter.Counter[int] `json:"hits"`
+	}
+	var r report
+	if err := json.Unmarshal([]byte(`{"hits":[[1,2],[3,4]]}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	assertMapEq(t, r.Hits, map[int]int64{1: 2, 3: 4})
+
+	var bad counter.Counter[int]
+	if err := json.Unmarshal([]byte(`[[1,"x"]]`), &bad); err == nil {
+		t.Fatal("expected error for non-numeric count")
+	}
+	if err := json.Unmarshal([]byte(`"nope"`), &bad); err == nil {
+		t.Fatal("expected error for string input")
+	}
+}
+
+func TestSafeCounterJSON(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	sc.Update(counter.Counter[string]{"x": 2})
+	data, err := json.Marshal(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `{"x":2}`)
+
+	var back counter.SafeCounter[string]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, back.Get("x"), int64(2))
+}
+
+func TestStringDeterministic(t *testing.T) {
+	c := counter.Counter[string]{"b": 1, "a": 1, "z": 3}
+	assertEqual(t, c.String(), "Counter{z:3, a:1, b:1}")
+}
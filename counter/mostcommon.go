@@ -0,0 +1,140 @@
+package counter
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// MostCommonFunc is MostCommon with a caller-supplied key ordering used to
+// break ties between equal counts (cmp follows the slices.SortFunc contract).
+// For 0 < n < len(c) it keeps a bounded heap of the best n entries, so the
+// cost is O(len(c) log n) instead of sorting every item.
+func (c Counter[T]) MostCommonFunc(n int, cmp func(a, b T) int) []Pair[T] {
+	rank := func(a, b Pair[T]) int {
+		if a.Count != b.Count {
+			if a.Count > b.Count {
+				return -1
+			}
+			return 1
+		}
+		return cmp(a.Key, b.Key)
+	}
+	if n <= 0 || n >= len(c) {
+		items := c.Items()
+		slices.SortFunc(items, rank)
+		return items
+	}
+	h := topK[T]{rank: rank, items: make([]Pair[T], 0, n)}
+	for k, v := range c {
+		h.offer(Pair[T]{k, v}, n)
+	}
+	slices.SortFunc(h.items, rank)
+	return h.items
+}
+
+// MostCommonOrdered is the fast path of MostCommon for cmp.Ordered keys:
+// ties are broken by the natural key order without any allocation.
+func MostCommonOrdered[T cmp.Ordered](c Counter[T], n int) []Pair[T] {
+	return c.MostCommonFunc(n, cmp.Compare[T])
+}
+
+// MostCommonFunc is the locked counterpart of Counter.MostCommonFunc.
+func (s *SafeCounter[T]) MostCommonFunc(n int, cmp func(a, b T) int) []Pair[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.MostCommonFunc(n, cmp)
+}
+
+// topK is a binary heap whose root is the worst-ranked entry kept so far.
+type topK[T comparable] struct {
+	rank  func(a, b Pair[T]) int
+	items []Pair[T]
+}
+
+// offer keeps p if it ranks among the best n entries seen so far.
+func (h *topK[T]) offer(p Pair[T], n int) {
+	if len(h.items) < n {
+		h.items = append(h.items, p)
+		h.up(len(h.items) - 1)
+		return
+	}
+	if h.rank(p, h.items[0]) < 0 {
+		h.items[0] = p
+		h.down(0)
+	}
+}
+
+// worse reports whether entry i ranks after entry j.
+func (h *topK[T]) worse(i, j int) bool { return h.rank(h.items[i], h.items[j]) > 0 }
+
+func (h *topK[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.worse(i, parent) {
+			return
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *topK[T]) down(i int) {
+	for {
+		worst, l, r := i, 2*i+1, 2*i+2
+		if l < len(h.items) && h.worse(l, worst) {
+			worst = l
+		}
+		if r < len(h.items) && h.worse(r, worst) {
+			worst = r
+		}
+		if worst == i {
+			return
+		}
+		h.items[i], h.items[worst] = h.items[worst], h.items[i]
+		i = worst
+	}
+}
+
+// defaultCompare picks the tie-break ordering used by MostCommon.
+// Built-in ordered key types compare naturally (so 9 sorts before 10);
+// anything else falls back to fmt.Sprint, as MostCommon always did.
+func defaultCompare[T comparable]() func(a, b T) int {
+	switch any(*new(T)).(type) {
+	case string:
+		return orderedAs[T, string]()
+	case int:
+		return orderedAs[T, int]()
+	case int8:
+		return orderedAs[T, int8]()
+	case int16:
+		return orderedAs[T, int16]()
+	case int32:
+		return orderedAs[T, int32]()
+	case int64:
+		return orderedAs[T, int64]()
+	case uint:
+		return orderedAs[T, uint]()
+	case uint8:
+		return orderedAs[T, uint8]()
+	case uint16:
+		return orderedAs[T, uint16]()
+	case uint32:
+		return orderedAs[T, uint32]()
+	case uint64:
+		return orderedAs[T, uint64]()
+	case uintptr:
+		return orderedAs[T, uintptr]()
+	case float32:
+		return orderedAs[T, float32]()
+	case float64:
+		return orderedAs[T, float64]()
+	}
+	return func(a, b T) int { return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b)) }
+}
+
+// orderedAs compares T values through their concrete ordered type O.
+// Only call it once the caller has checked that T is exactly O.
+func orderedAs[T comparable, O cmp.Ordered]() func(a, b T) int {
+	return func(a, b T) int { return cmp.Compare(any(a).(O), any(b).(O)) }
+}
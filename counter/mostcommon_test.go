@@ -0,0 +1,86 @@
+package counter_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestMostCommonNumericTieBreak(t *testing.T) {
+	c := counter.Counter[int]{10: 1, 9: 1, 100: 2, 2: 1}
+	want := []counter.Pair[int]{{100, 2}, {2, 1}, {9, 1}, {10, 1}}
+	assertEqual(t, len(c.MostCommon(0)), len(want))
+	for i, p := range c.MostCommon(0) {
+		assertEqual(t, p, want[i])
+	}
+	for i, p := range counter.MostCommonOrdered(c, 3) {
+		assertEqual(t, p, want[i])
+	}
+}
+
+func TestMostCommonTopKMatchesFullSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	c := counter.New[int]()
+	for i := 0; i < 20000; i++ {
+		c.Add(rng.Intn(3000))
+	}
+	all := c.MostCommon(0)
+	for _, n := range []int{1, 5, 20, len(c) - 1} {
+		got := c.MostCommon(n)
+		assertEqual(t, len(got), n)
+		for i := range got {
+			if got[i] != all[i] {
+				t.Fatalf("n=%d idx %d: got %v want %v", n, i, got[i], all[i])
+			}
+		}
+	}
+}
+
+func TestMostCommonFuncCustomOrder(t *testing.T) {
+	c := counter.Counter[string]{"b": 1, "A": 1, "c": 3}
+	fold := func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) }
+	got := c.MostCommonFunc(2, fold)
+	assertEqual(t, got[0], counter.Pair[string]{"c", 3})
+	assertEqual(t, got[1], counter.Pair[string]{"A", 1})
+
+	sc := counter.NewSafe[string]()
+	sc.Update(c)
+	assertEqual(t, sc.MostCommonFunc(1, fold)[0], counter.Pair[string]{"c", 3})
+}
+
+func TestMostCommonNonOrderedKeys(t *testing.T) {
+	type point struct{ X, Y int }
+	c := counter.Counter[point]{{2, 1}: 1, {1, 2}: 1, {0, 0}: 5}
+	got := c.MostCommon(0)
+	assertEqual(t, got[0].Key, point{0, 0})
+	assertEqual(t, got[1].Key, point{1, 2}) // fmt.Sprint: "{1 2}" < "{2 1}"
+}
+
+func bigCounter() counter.Counter[int] {
+	rng := rand.New(rand.NewSource(42))
+	c := counter.New[int]()
+	for i := 0; i < 1_000_000; i++ {
+		c.Add(int(rng.ExpFloat64() * 50000))
+	}
+	return c
+}
+
+func BenchmarkMostCommon_Top20_Large(b *testing.B) {
+	c := bigCounter()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.MostCommon(20)
+	}
+}
+
+func BenchmarkMostCommonOrdered_Top20_Large(b *testing.B) {
+	c := bigCounter()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = counter.MostCommonOrdered(c, 20)
+	}
+}
@@ -0,0 +1,197 @@
+package counter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Overflow handling
+//
+// AddN, Update and Total wrap around silently on int64 overflow, like plain
+// Go arithmetic. Where counts can get that large (byte totals, for example)
+// use the variants below instead:
+//
+//   - AddNChecked, UpdateChecked and TotalChecked return ErrOverflow and
+//     leave the counter unchanged;
+//   - AddNSaturating and UpdateSaturating clamp to math.MaxInt64 or
+//     math.MinInt64.
+//
+// Elements has the same problem in another form: large counts ask for a
+// slice that cannot be allocated. TryElementsMax bounds its size and
+// ElementsSeq avoids the slice altogether.
+
+// ErrOverflow is returned when a count or total does not fit in an int64,
+// or when Elements would need a slice larger than the runtime can allocate.
+var ErrOverflow = errors.New("counter: overflow")
+
+// addInt64 returns a + b and whether the sum overflowed.
+func addInt64(a, b int64) (int64, bool) {
+	sum := a + b
+	return sum, (b > 0 && sum < a) || (b < 0 && sum > a)
+}
+
+// saturate returns a + b clamped to the int64 range, and whether it clamped.
+func saturate(a, b int64) (int64, bool) {
+	sum, overflow := addInt64(a, b)
+	switch {
+	case !overflow:
+		return sum, false
+	case b > 0:
+		return math.MaxInt64, true
+	default:
+		return math.MinInt64, true
+	}
+}
+
+// AddNChecked is AddN that returns ErrOverflow, leaving c unchanged, if the
+// new count would not fit in an int64.
+func (c Counter[T]) AddNChecked(k T, n int64) error {
+	if _, overflow := addInt64(c[k], n); overflow {
+		return fmt.Errorf("%w: key %v: %d + %d", ErrOverflow, k, c[k], n)
+	}
+	c.AddN(k, n)
+	return nil
+}
+
+// UpdateChecked is Update that returns ErrOverflow if any count would
+// overflow. It checks every key first, so on error c is left unchanged.
+func (c Counter[T]) UpdateChecked(other Counter[T]) error {
+	for k, v := range other {
+		if _, overflow := addInt64(c[k], v); overflow {
+			return fmt.Errorf("%w: key %v: %d + %d", ErrOverflow, k, c[k], v)
+		}
+	}
+	c.Update(other)
+	return nil
+}
+
+// AddNSaturating is AddN that clamps the count to [math.MinInt64,
+// math.MaxInt64] instead of wrapping. It reports whether it clamped.
+func (c Counter[T]) AddNSaturating(k T, n int64) (saturated bool) {
+	if n == 0 {
+		return false
+	}
+	c[k], saturated = saturate(c[k], n)
+	if c[k] == 0 {
+		delete(c, k)
+	}
+	return saturated
+}
+
+// UpdateSaturating is Update with AddNSaturating's clamping. It reports
+// whether any count was clamped.
+func (c Counter[T]) UpdateSaturating(other Counter[T]) (saturated bool) {
+	for k, v := range other {
+		if c.AddNSaturating(k, v) {
+			saturated = true
+		}
+	}
+	return saturated
+}
+
+// TotalChecked is Total that returns ErrOverflow if the sum of the positive
+// counts does not fit in an int64.
+func (c Counter[T]) TotalChecked() (int64, error) {
+	var sum int64
+	for _, v := range c {
+		if v <= 0 {
+			continue
+		}
+		var overflow bool
+		if sum, overflow = addInt64(sum, v); overflow {
+			return 0, fmt.Errorf("%w: total exceeds %d", ErrOverflow, int64(math.MaxInt64))
+		}
+	}
+	return sum, nil
+}
+
+// maxSliceBytes approximates the largest allocation the Go runtime accepts
+// (1<<48 bytes on 64-bit platforms, the int range on 32-bit ones).
+const maxSliceBytes = min(uint64(math.MaxInt), 1<<48)
+
+// TryElements is Elements that returns ErrOverflow instead of panicking when
+// the counts add up to more elements than the runtime can allocate at all.
+// It does not protect against results that are allocatable in principle but
+// larger than the available memory: that still ends the process. Bound the
+// result with TryElementsMax, or use ElementsSeq to visit the elements
+// without materialising them.
+func (c Counter[T]) TryElements() ([]T, error) {
+	size := max(uint64(unsafe.Sizeof(*new(T))), 1)
+	return c.elements(maxSliceBytes / size)
+}
+
+// TryElementsMax is Elements that returns ErrOverflow, without allocating,
+// if the result would hold more than limit elements.
+func (c Counter[T]) TryElementsMax(limit int) ([]T, error) {
+	return c.elements(uint64(max(limit, 0)))
+}
+
+// elements builds the Elements slice if it holds at most limit items.
+func (c Counter[T]) elements(limit uint64) ([]T, error) {
+	var total uint64
+	for _, v := range c {
+		if v <= 0 {
+			continue
+		}
+		total += uint64(v)
+		if total > limit {
+			return nil, fmt.Errorf("%w: Elements needs more than %d items; use ElementsSeq", ErrOverflow, limit)
+		}
+	}
+	out := make([]T, 0, int(total))
+	for k, v := range c {
+		for i := int64(0); i < v; i++ {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+// The SafeCounter variants below mirror the Counter methods with locking.
+
+// AddNChecked is the locked counterpart of Counter.AddNChecked.
+func (s *SafeCounter[T]) AddNChecked(k T, n int64) (err error) {
+	s.writeKey(k, func() { err = s.c.AddNChecked(k, n) })
+	return err
+}
+
+// UpdateChecked is the locked counterpart of Counter.UpdateChecked.
+func (s *SafeCounter[T]) UpdateChecked(other Counter[T]) (err error) {
+	s.writeKeys(keysOf(other), func() { err = s.c.UpdateChecked(other) })
+	return err
+}
+
+// AddNSaturating is the locked counterpart of Counter.AddNSaturating.
+func (s *SafeCounter[T]) AddNSaturating(k T, n int64) (saturated bool) {
+	s.writeKey(k, func() { saturated = s.c.AddNSaturating(k, n) })
+	return saturated
+}
+
+// UpdateSaturating is the locked counterpart of Counter.UpdateSaturating.
+func (s *SafeCounter[T]) UpdateSaturating(other Counter[T]) (saturated bool) {
+	s.writeKeys(keysOf(other), func() { saturated = s.c.UpdateSaturating(other) })
+	return saturated
+}
+
+// TotalChecked is the locked counterpart of Counter.TotalChecked.
+func (s *SafeCounter[T]) TotalChecked() (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.TotalChecked()
+}
+
+// TryElements is the locked counterpart of Counter.TryElements.
+func (s *SafeCounter[T]) TryElements() ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.TryElements()
+}
+
+// TryElementsMax is the locked counterpart of Counter.TryElementsMax.
+func (s *SafeCounter[T]) TryElementsMax(limit int) ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.TryElementsMax(limit)
+}
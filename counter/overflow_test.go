@@ -0,0 +1,111 @@
+package counter_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestCheckedArithmetic(t *testing.T) {
+	c := counter.Counter[string]{"bytes": math.MaxInt64 - 10, "low": math.MinInt64 + 1}
+
+	if err := c.AddNChecked("bytes", 10); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, c.Get("bytes"), int64(math.MaxInt64))
+	if err := c.AddNChecked("bytes", 1); !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("want ErrOverflow, got %v", err)
+	}
+	if err := c.AddNChecked("low", -2); !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("want ErrOverflow on underflow, got %v", err)
+	}
+	assertEqual(t, c.Get("bytes"), int64(math.MaxInt64))
+
+	// UpdateChecked 出错时不做任何修改
+	before := c.Clone()
+	err := c.UpdateChecked(counter.Counter[string]{"new": 5, "bytes": 1})
+	if !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("want ErrOverflow, got %v", err)
+	}
+	assertMapEq(t, c, before)
+	if err := c.UpdateChecked(counter.Counter[string]{"bytes": -1, "new": 5}); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, c.Get("new"), int64(5))
+
+	if _, err := c.TotalChecked(); !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("want ErrOverflow from TotalChecked, got %v", err)
+	}
+	total, err := counter.Counter[int]{1: 2, 2: 3, 3: -4}.TotalChecked()
+	if err != nil || total != 5 {
+		t.Fatalf("TotalChecked = %d, %v", total, err)
+	}
+}
+
+func TestSaturatingArithmetic(t *testing.T) {
+	c := counter.Counter[string]{"a": math.MaxInt64 - 1, "b": math.MinInt64 + 1}
+	assertEqual(t, c.AddNSaturating("a", 1), false)
+	assertEqual(t, c.AddNSaturating("a", 100), true)
+	assertEqual(t, c.Get("a"), int64(math.MaxInt64))
+	assertEqual(t, c.AddNSaturating("b", -5), true)
+	assertEqual(t, c.Get("b"), int64(math.MinInt64))
+
+	assertEqual(t, c.UpdateSaturating(counter.Counter[string]{"a": math.MaxInt64, "c": 1}), true)
+	assertEqual(t, c.Get("a"), int64(math.MaxInt64))
+	assertEqual(t, c.UpdateSaturating(counter.Counter[string]{"c": -1}), false)
+	assertEqual(t, len(c), 2) // 归零即删除，同 AddN
+
+	sc := counter.NewSafe[string]()
+	sc.Set("x", math.MaxInt64)
+	assertEqual(t, sc.AddNSaturating("x", 1), true)
+	if err := sc.AddNChecked("x", 1); !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("SafeCounter.AddNChecked: %v", err)
+	}
+	if err := sc.UpdateChecked(counter.Counter[string]{"x": -1}); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, sc.Get("x"), int64(math.MaxInt64-1))
+}
+
+func TestElementsTooLarge(t *testing.T) {
+	huge := counter.Counter[string]{"a": math.MaxInt64, "b": math.MaxInt64}
+	if _, err := huge.TryElements(); !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("want ErrOverflow, got %v", err)
+	}
+	func() {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, counter.ErrOverflow) {
+				t.Fatalf("Elements should panic with ErrOverflow, got %v", err)
+			}
+		}()
+		huge.Elements()
+	}()
+
+	// 流式遍历不受影响
+	n := 0
+	for range huge.ElementsSeq() {
+		if n++; n == 10 {
+			break
+		}
+	}
+	assertEqual(t, n, 10)
+
+	// 16 TiB 的字符串切片在运行时上限之内，只能靠调用方给出的上限挡住
+	big := counter.Counter[string]{"x": 1 << 40}
+	if _, err := big.TryElementsMax(1 << 20); !errors.Is(err, counter.ErrOverflow) {
+		t.Fatalf("want ErrOverflow from TryElementsMax, got %v", err)
+	}
+	els, err := counter.Counter[string]{"a": 2, "b": -1}.TryElementsMax(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSliceEq(t, els, []string{"a", "a"})
+
+	els, err = counter.Counter[string]{"a": 2, "b": -1}.TryElements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSliceEq(t, els, []string{"a", "a"})
+}
@@ -0,0 +1,112 @@
+package counter
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// parallelBatch is how many items a worker handles between context checks
+// (and, for iter.Seq sources, how many items travel per channel send).
+const parallelBatch = 4096
+
+// ParallelCountSlice counts xs with the given number of workers (GOMAXPROCS
+// if <= 0). Each worker counts its own chunk into a private Counter and the
+// partial results are merged with Update, so there is no lock contention.
+// If ctx is cancelled, it stops early and returns ctx.Err().
+func ParallelCountSlice[T comparable](ctx context.Context, xs []T, workers int) (Counter[T], error) {
+	workers = parallelWorkers(workers, len(xs))
+	parts := make([]Counter[T], workers)
+	chunk := (len(xs) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := range parts {
+		lo, hi := min(w*chunk, len(xs)), min((w+1)*chunk, len(xs))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := New[T]()
+			for i := lo; i < hi; i += parallelBatch {
+				if ctx.Err() != nil {
+					return
+				}
+				local.UpdateSlice(xs[i:min(i+parallelBatch, hi)])
+			}
+			parts[w] = local
+		}()
+	}
+	wg.Wait()
+	return mergeParts(ctx, parts)
+}
+
+// ParallelCount counts the items of seq with the given number of workers
+// (GOMAXPROCS if <= 0). seq is consumed by a single goroutine, which hands
+// batches to workers that count into private Counters merged at the end.
+// If ctx is cancelled, it stops pulling from seq and returns ctx.Err().
+func ParallelCount[T comparable](ctx context.Context, seq iter.Seq[T], workers int) (Counter[T], error) {
+	workers = parallelWorkers(workers, -1)
+	batches := make(chan []T, workers)
+	parts := make([]Counter[T], workers)
+
+	var wg sync.WaitGroup
+	for w := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := New[T]()
+			for b := range batches {
+				local.UpdateSlice(b)
+			}
+			parts[w] = local
+		}()
+	}
+
+	batch := make([]T, 0, parallelBatch)
+	send := func() bool {
+		select {
+		case batches <- batch:
+			batch = make([]T, 0, parallelBatch)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for x := range seq {
+		batch = append(batch, x)
+		if len(batch) == parallelBatch && !send() {
+			break
+		}
+	}
+	if len(batch) > 0 && ctx.Err() == nil {
+		send()
+	}
+	close(batches)
+	wg.Wait()
+	return mergeParts(ctx, parts)
+}
+
+func parallelWorkers(workers, n int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n >= 0 {
+		workers = max(1, min(workers, n))
+	}
+	return workers
+}
+
+// mergeParts folds worker results into the largest one.
+func mergeParts[T comparable](ctx context.Context, parts []Counter[T]) (Counter[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := parts[0]
+	for _, p := range parts[1:] {
+		if len(p) > len(out) {
+			out, p = p, out
+		}
+		out.Update(p)
+	}
+	return out, nil
+}
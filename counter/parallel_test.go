@@ -0,0 +1,102 @@
+package counter_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func parallelInput(n int) []int {
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = (i * 7919) % 1000
+	}
+	return xs
+}
+
+func TestParallelCountMatchesSerial(t *testing.T) {
+	xs := parallelInput(100_003)
+	want := counter.FromSlice(xs)
+	for _, workers := range []int{0, 1, 3, 16} {
+		got, err := counter.ParallelCountSlice(context.Background(), xs, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, got.Equal(want), true)
+
+		got, err = counter.ParallelCount(context.Background(), slices.Values(xs), workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, got.Equal(want), true)
+	}
+
+	empty, err := counter.ParallelCountSlice(context.Background(), []string{}, 4)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("empty input: %v %v", empty, err)
+	}
+}
+
+func TestParallelCountCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// 无限序列：只有取消才能结束
+	infinite := iter.Seq[int](func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if i == 50_000 {
+				cancel()
+			}
+			if !yield(i % 10) {
+				return
+			}
+		}
+	})
+	c, err := counter.ParallelCount(ctx, infinite, 4)
+	if !errors.Is(err, context.Canceled) || c != nil {
+		t.Fatalf("want context.Canceled, got %v (%d keys)", err, len(c))
+	}
+
+	if _, err := counter.ParallelCountSlice(ctx, parallelInput(10), 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
+
+// 对比：所有 goroutine 共用一个 SafeCounter vs 每个 worker 本地计数再合并
+
+func BenchmarkCount_SafeCounterShared(b *testing.B) {
+	xs := parallelInput(1 << 20)
+	for i := 0; i < b.N; i++ {
+		sc := counter.NewSafe[int]()
+		var wg sync.WaitGroup
+		const workers = 8
+		chunk := len(xs) / workers
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(part []int) {
+				defer wg.Done()
+				for _, x := range part {
+					sc.Add(x)
+				}
+			}(xs[w*chunk : (w+1)*chunk])
+		}
+		wg.Wait()
+	}
+}
+
+func BenchmarkCount_ParallelCountSlice(b *testing.B) {
+	xs := parallelInput(1 << 20)
+	for i := 0; i < b.N; i++ {
+		_, _ = counter.ParallelCountSlice(context.Background(), xs, 8)
+	}
+}
+
+func BenchmarkCount_ParallelCountSeq(b *testing.B) {
+	xs := parallelInput(1 << 20)
+	for i := 0; i < b.N; i++ {
+		_, _ = counter.ParallelCount(context.Background(), slices.Values(xs), 8)
+	}
+}
@@ -0,0 +1,180 @@
+// Package prom publishes counter.SafeCounter contents over HTTP in the
+// Prometheus text exposition format (version 0.0.4), without depending on
+// the Prometheus client library.
+//
+//	reg := prom.NewRegistry()
+//	prom.Register(reg, prom.Metric{Name: "http_requests", Help: "Requests by path."}, hits)
+//	r.Handle("/metrics", reg) // e.g. on a chi router
+package prom
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// ContentType is the Content-Type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Metric describes how one counter is exposed.
+type Metric struct {
+	// Name is the metric name, e.g. "login_failures".
+	Name string
+	// Help is the optional HELP text.
+	Help string
+	// Label is the label carrying the key; "key" if empty.
+	Label string
+	// Type is "gauge" (the default) or "counter". Use "counter" only if the
+	// counts never decrease.
+	Type string
+}
+
+type sample struct {
+	label string
+	value int64
+}
+
+type entry struct {
+	Metric
+	collect func() []sample
+}
+
+// Registry is a set of named counters. It implements http.Handler.
+type Registry struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry { return &Registry{entries: make(map[string]entry)} }
+
+// Register adds c to r under m.Name. Keys are rendered with fmt.Sprint.
+// It returns an error for invalid or duplicate names.
+func Register[T comparable](r *Registry, m Metric, c *counter.SafeCounter[T]) error {
+	if m.Label == "" {
+		m.Label = "key"
+	}
+	if m.Type == "" {
+		m.Type = "gauge"
+	}
+	switch {
+	case !metricNameRE.MatchString(m.Name):
+		return fmt.Errorf("prom: invalid metric name %q", m.Name)
+	case !labelNameRE.MatchString(m.Label) || strings.HasPrefix(m.Label, "__"):
+		return fmt.Errorf("prom: invalid label name %q", m.Label)
+	case m.Type != "gauge" && m.Type != "counter":
+		return fmt.Errorf("prom: unsupported metric type %q", m.Type)
+	}
+
+	collect := func() []sample {
+		items := c.Items()
+		out := make([]sample, len(items))
+		for i, p := range items {
+			out[i] = sample{fmt.Sprint(p.Key), p.Count}
+		}
+		return out
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.entries[m.Name]; dup {
+		return fmt.Errorf("prom: metric %q already registered", m.Name)
+	}
+	r.entries[m.Name] = entry{m, collect}
+	return nil
+}
+
+// Unregister removes the metric with the given name.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+// Handler returns an http.Handler serving a single counter.
+func Handler[T comparable](m Metric, c *counter.SafeCounter[T]) (http.Handler, error) {
+	r := NewRegistry()
+	if err := Register(r, m, c); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ServeHTTP writes all metrics in the text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	if req.Method == http.MethodHead {
+		return
+	}
+	r.WriteTo(w)
+}
+
+// WriteTo writes all metrics, ordered by name and then by label value, so
+// the output is deterministic. It implements io.WriterTo.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	entries := make([]entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+	slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(a.Name, b.Name) })
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, e := range entries {
+		if e.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", e.Name, escapeHelp(e.Help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", e.Name, e.Type)
+		samples := e.collect()
+		slices.SortFunc(samples, func(a, b sample) int { return cmp.Compare(a.label, b.label) })
+		for _, s := range samples {
+			fmt.Fprintf(bw, "%s{%s=\"%s\"} %s\n", e.Name, e.Label, escapeLabel(s.label),
+				strconv.FormatInt(s.value, 10))
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// escapeHelp escapes backslash and line feed, as required in HELP lines.
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
+
+// escapeLabel escapes backslash, double quote and line feed in label values.
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
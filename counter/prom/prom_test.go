@@ -0,0 +1,108 @@
+package prom_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/prom"
+)
+
+func TestRegistryExposition(t *testing.T) {
+	paths := counter.NewSafe[string]()
+	paths.Update(counter.Counter[string]{"/b": 2, "/a": 5, `we"ird\path` + "\n": 1})
+	codes := counter.NewSafe[int]()
+	codes.Update(counter.Counter[int]{500: 1, 200: 7})
+
+	reg := prom.NewRegistry()
+	if err := prom.Register(reg, prom.Metric{Name: "http_requests", Help: "Requests by path.\nSee docs \\ wiki.", Label: "path"}, paths); err != nil {
+		t.Fatal(err)
+	}
+	if err := prom.Register(reg, prom.Metric{Name: "http_codes_total", Type: "counter"}, codes); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != prom.ContentType {
+		t.Fatalf("status %d content-type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	want := `# TYPE http_codes_total counter
+http_codes_total{key="200"} 7
+http_codes_total{key="500"} 1
+# HELP http_requests Requests by path.\nSee docs \\ wiki.
+# TYPE http_requests gauge
+http_requests{path="/a"} 5
+http_requests{path="/b"} 2
+http_requests{path="we\"ird\\path\n"} 1
+`
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("\n got:\n%s\nwant:\n%s", got, want)
+	}
+
+	reg.Unregister("http_requests")
+	rec = httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Body.String(); got != "# TYPE http_codes_total counter\nhttp_codes_total{key=\"200\"} 7\nhttp_codes_total{key=\"500\"} 1\n" {
+		t.Fatalf("after unregister:\n%s", got)
+	}
+}
+
+func TestHandlerLiveAndMethods(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	h, err := prom.Handler(prom.Metric{Name: "logins"}, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	sc.Add("alice")
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	// 注册之后写入的值也要出现在页面上
+	if !strings.Contains(string(body), `logins{key="alice"} 1`+"\n") {
+		t.Fatalf("live value missing from body:\n%s", body)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST: status %d", rec.Code)
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	reg := prom.NewRegistry()
+	bad := []prom.Metric{
+		{Name: "1abc"},
+		{Name: "ok", Label: "bad-label"},
+		{Name: "ok", Label: "__reserved"},
+		{Name: "ok", Type: "histogram"},
+	}
+	for _, m := range bad {
+		if err := prom.Register(reg, m, sc); err == nil {
+			t.Fatalf("%+v: expected error", m)
+		}
+	}
+	if err := prom.Register(reg, prom.Metric{Name: "dup"}, sc); err != nil {
+		t.Fatal(err)
+	}
+	if err := prom.Register(reg, prom.Metric{Name: "dup"}, sc); err == nil {
+		t.Fatal("expected duplicate error")
+	}
+}
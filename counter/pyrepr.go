@@ -0,0 +1,294 @@
+package counter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PyRepr formats c exactly like Python's repr(Counter), e.g.
+//
+//	Counter({'a': 2, 'b': 1})
+//
+// Entries are in MostCommon order. CPython breaks ties by insertion order,
+// which a Go map does not have, so tied keys appear in ascending key order.
+// String keys are quoted with Python's rules, integer keys print as
+// decimals and bool keys as True/False; any other key is quoted from its
+// fmt.Sprint form.
+func (c Counter[T]) PyRepr() string {
+	pairs := c.MostCommon(0)
+	if len(pairs) == 0 {
+		return "Counter()"
+	}
+	var b strings.Builder
+	b.WriteString("Counter({")
+	for i, p := range pairs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writePyKey(&b, p.Key)
+		b.WriteString(": ")
+		b.WriteString(strconv.FormatInt(p.Count, 10))
+	}
+	b.WriteString("})")
+	return b.String()
+}
+
+func writePyKey(b *strings.Builder, k any) {
+	v := reflect.ValueOf(k)
+	switch v.Kind() {
+	case reflect.String:
+		b.WriteString(pyQuote(v.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Bool:
+		if v.Bool() {
+			b.WriteString("True")
+		} else {
+			b.WriteString("False")
+		}
+	default:
+		b.WriteString(pyQuote(fmt.Sprint(k)))
+	}
+}
+
+// pyQuote returns repr(s) for a Python str. Python prefers single quotes and
+// switches to double quotes only if s contains ' but no ". Go's
+// unicode.IsPrint matches Python's str.isprintable.
+func pyQuote(s string) string {
+	quote := byte('\'')
+	if strings.ContainsRune(s, '\'') && !strings.ContainsRune(s, '"') {
+		quote = '"'
+	}
+	var b strings.Builder
+	b.WriteByte(quote)
+	for _, r := range s {
+		switch {
+		case r == rune(quote) || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r < 0x100:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r < 0x10000:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			fmt.Fprintf(&b, `\U%08x`, r)
+		}
+	}
+	b.WriteByte(quote)
+	return b.String()
+}
+
+// PyKey constrains the key types ParsePyRepr can produce.
+type PyKey interface {
+	~string | ~int | ~int64
+}
+
+// ParsePyRepr parses the output of Python's repr(Counter) (or PyRepr) back
+// into a Counter. String keys need a string T and integer keys an integer T.
+func ParsePyRepr[T PyKey](s string) (Counter[T], error) {
+	p := pyParser{s: s}
+	out := New[T]()
+	stringKeys := reflect.TypeFor[T]().Kind() == reflect.String
+
+	if err := p.expect("Counter("); err != nil {
+		return nil, err
+	}
+	if p.skip(); p.peek() == ')' {
+		p.pos++
+		return out, p.end()
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		if p.skip(); p.peek() == '}' {
+			p.pos++
+			break
+		}
+		var k T
+		if c := p.peek(); c == '\'' || c == '"' {
+			if !stringKeys {
+				return nil, p.errorf("string key for integer counter")
+			}
+			str, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			reflect.ValueOf(&k).Elem().SetString(str)
+		} else {
+			if stringKeys {
+				return nil, p.errorf("integer key for string counter")
+			}
+			n, err := p.int()
+			if err != nil {
+				return nil, err
+			}
+			kv := reflect.ValueOf(&k).Elem()
+			if kv.OverflowInt(n) {
+				return nil, p.errorf("key %d overflows %s", n, kv.Type())
+			}
+			kv.SetInt(n)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		n, err := p.int()
+		if err != nil {
+			return nil, err
+		}
+		out[k] = n
+
+		if p.skip(); p.peek() == ',' {
+			p.pos++
+			continue
+		}
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return out, p.end()
+}
+
+// pyParser is a tiny scanner over a Python Counter repr.
+type pyParser struct {
+	s   string
+	pos int
+}
+
+func (p *pyParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("counter: parse repr at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pyParser) skip() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *pyParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *pyParser) expect(tok string) error {
+	p.skip()
+	if !strings.HasPrefix(p.s[p.pos:], tok) {
+		return p.errorf("want %q", tok)
+	}
+	p.pos += len(tok)
+	return nil
+}
+
+func (p *pyParser) end() error {
+	if p.skip(); p.pos != len(p.s) {
+		return p.errorf("trailing data")
+	}
+	return nil
+}
+
+// int parses a decimal integer literal with Python's rules: underscores may
+// only separate digits, and a literal with a leading zero must be all zeros
+// ("00" is 0, "010" is a syntax error).
+func (p *pyParser) int() (int64, error) {
+	p.skip()
+	start := p.pos
+	if c := p.peek(); c == '-' || c == '+' {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] >= '0' && p.s[p.pos] <= '9' || p.s[p.pos] == '_') {
+		p.pos++
+	}
+	lit := p.s[digits:p.pos]
+	switch {
+	case lit == "":
+		p.pos = start
+		return 0, p.errorf("want integer")
+	case lit[0] == '_' || lit[len(lit)-1] == '_' || strings.Contains(lit, "__"):
+		p.pos = start
+		return 0, p.errorf("bad integer %q: misplaced underscore", lit)
+	case lit[0] == '0' && strings.Trim(lit, "0_") != "":
+		p.pos = start
+		return 0, p.errorf("bad integer %q: leading zeros are not allowed", lit)
+	}
+	n, err := strconv.ParseInt(p.s[start:digits]+strings.ReplaceAll(lit, "_", ""), 10, 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("bad integer: %v", err)
+	}
+	return n, nil
+}
+
+// str parses a single- or double-quoted Python string literal.
+func (p *pyParser) str() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.s) {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			r, w := utf8.DecodeRuneInString(p.s[p.pos:])
+			b.WriteRune(r)
+			p.pos += w
+		}
+	}
+}
+
+var pySimpleEscapes = map[byte]string{
+	'\\': "\\", '\'': "'", '"': "\"", 'n': "\n", 'r': "\r", 't': "\t",
+	'a': "\a", 'b': "\b", 'f': "\f", 'v': "\v", '0': "\x00",
+}
+
+func (p *pyParser) escape(b *strings.Builder) error {
+	if p.pos+1 >= len(p.s) {
+		return p.errorf("unterminated escape")
+	}
+	c := p.s[p.pos+1]
+	p.pos += 2
+	if s, ok := pySimpleEscapes[c]; ok {
+		b.WriteString(s)
+		return nil
+	}
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+	if digits == 0 || p.pos+digits > len(p.s) {
+		return p.errorf("unsupported escape \\%c", c)
+	}
+	r, err := strconv.ParseUint(p.s[p.pos:p.pos+digits], 16, 32)
+	if err != nil || r > unicode.MaxRune {
+		return p.errorf("bad escape \\%c%s", c, p.s[p.pos:p.pos+digits])
+	}
+	b.WriteRune(rune(r))
+	p.pos += digits
+	return nil
+}
@@ -0,0 +1,107 @@
+package counter_test
+
+import (
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// Golden strings below were printed by CPython 3.11 with repr(Counter(...)).
+// Tied keys were inserted in ascending order so that CPython's insertion-order
+// tie-break matches ours.
+
+func TestPyReprStringGolden(t *testing.T) {
+	cases := []struct {
+		c    counter.Counter[string]
+		want string
+	}{
+		{counter.Counter[string]{}, `Counter()`},
+		{counter.Counter[string]{"b": 1, "a": 2, "c": 1}, `Counter({'a': 2, 'b': 1, 'c': 1})`},
+		{
+			counter.Counter[string]{"it's": 3, `say "hi"`: 2, `both ' "`: 1},
+			`Counter({"it's": 3, 'say "hi"': 2, 'both \' "': 1})`,
+		},
+		{
+			counter.Counter[string]{
+				"tab\there": 1, "nl\n": 2, `back\slash`: 3, "\x00\x7f": 4,
+				"café 日本": 5, "\u200b": 6, "\U0001F600": 7, "\u00a0": 8,
+			},
+			`Counter({'\xa0': 8, '😀': 7, '\u200b': 6, 'café 日本': 5, '\x00\x7f': 4, 'back\\slash': 3, 'nl\n': 2, 'tab\there': 1})`,
+		},
+		{counter.Counter[string]{"neg": -2, "zero": 0, "pos": 1}, `Counter({'pos': 1, 'zero': 0, 'neg': -2})`},
+	}
+	for _, tc := range cases {
+		got := tc.c.PyRepr()
+		assertEqual(t, got, tc.want)
+
+		back, err := counter.ParsePyRepr[string](tc.want)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tc.want, err)
+		}
+		if !back.Equal(tc.c) || len(back) != len(tc.c) {
+			t.Fatalf("round trip %s: got %v", tc.want, back)
+		}
+	}
+}
+
+func TestPyReprIntGolden(t *testing.T) {
+	c := counter.Counter[int]{10: 1, 9: 1, -3: 5, 100: 2}
+	const want = `Counter({-3: 5, 100: 2, 9: 1, 10: 1})`
+	assertEqual(t, c.PyRepr(), want)
+
+	back, err := counter.ParsePyRepr[int](want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, back.Equal(c), true)
+
+	assertEqual(t, counter.Counter[bool]{true: 1}.PyRepr(), `Counter({True: 1})`)
+}
+
+func TestParsePyReprLenient(t *testing.T) {
+	c, err := counter.ParsePyRepr[int64]("Counter( {\n 1_000: 2 ,\n 2: 3, } )")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMapEq(t, c, map[int64]int64{1000: 2, 2: 3})
+
+	s, err := counter.ParsePyRepr[string](`Counter({"\x41é\U0001F600\t": 1})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, s.Get("Aé😀\t"), int64(1))
+
+	z, err := counter.ParsePyRepr[string](`Counter({'a': 00, 'b': +1_0, 'c': 0_0})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(z), 3)
+	assertEqual(t, z.Get("b"), int64(10))
+}
+
+func TestParsePyReprErrors(t *testing.T) {
+	for _, in := range []string{
+		``,
+		`Counter`,
+		`Counter({'a' 1})`,
+		`Counter({'a': 1)`,
+		`Counter({'a': x})`,
+		`Counter({'a: 1})`,
+		`Counter({'\q': 1})`,
+		`Counter({1: 1})`,
+		`Counter({'a': 1}) extra`,
+		`Counter({'a': 010})`, // Python 中是语法错误，不是八进制
+		`Counter({'a': 0o10})`,
+		`Counter({'a': 1__0})`,
+		`Counter({'a': _1})`,
+		`Counter({'a': 1_})`,
+		`Counter({'a': -})`,
+	} {
+		if _, err := counter.ParsePyRepr[string](in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+	if _, err := counter.ParsePyRepr[int](`Counter({'a': 1})`); err == nil {
+		t.Error("string key into int counter: expected error")
+	}
+}
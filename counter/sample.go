@@ -0,0 +1,183 @@
+package counter
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+)
+
+// ErrSampleSize is returned by SampleN when more distinct draws are requested
+// than the counter holds, or when sampling from an empty counter.
+var ErrSampleSize = errors.New("counter: sample larger than population")
+
+// Sampler draws keys at random, weighted by their counts, without building
+// the Elements() bag. It uses Walker's alias method, so Sample is O(1).
+//
+// Keys are laid out in MostCommon tie-break order, so a Sampler built from
+// equal counters and driven by equally seeded generators (see SeededRand)
+// yields the same sequence on every run.
+type Sampler[T comparable] struct {
+	keys    []T
+	weights []int64 // positive counts, parallel to keys
+	total   int64
+	prob    []float64
+	alias   []int
+}
+
+// NewSampler builds a sampler over the positive counts of c.
+// Later changes to c do not affect the sampler.
+func NewSampler[T comparable](c Counter[T]) *Sampler[T] {
+	s := &Sampler[T]{}
+	for k, v := range c {
+		if v > 0 {
+			s.keys = append(s.keys, k)
+		}
+	}
+	slices.SortFunc(s.keys, defaultCompare[T]())
+	s.weights = make([]int64, len(s.keys))
+	for i, k := range s.keys {
+		s.weights[i] = c[k]
+		s.total += c[k]
+	}
+	s.buildAlias()
+	return s
+}
+
+// buildAlias fills prob and alias using Vose's stable variant.
+func (s *Sampler[T]) buildAlias() {
+	n := len(s.keys)
+	s.prob = make([]float64, n)
+	s.alias = make([]int, n)
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range s.weights {
+		scaled[i] = float64(w) * float64(n) / float64(s.total)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		l, g := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		s.prob[l], s.alias[l] = scaled[l], g
+		scaled[g] -= 1 - scaled[l]
+		if scaled[g] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, g)
+		}
+	}
+	// Leftovers are 1 up to rounding error.
+	for _, i := range large {
+		s.prob[i] = 1
+	}
+	for _, i := range small {
+		s.prob[i] = 1
+	}
+}
+
+// SeededRand returns a deterministic generator for reproducible sampling.
+func SeededRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+// Len returns the number of distinct keys that can be drawn.
+func (s *Sampler[T]) Len() int { return len(s.keys) }
+
+// Total returns the sum of the weights, i.e. the size of the bag.
+func (s *Sampler[T]) Total() int64 { return s.total }
+
+// Sample draws one key with probability count/Total. A nil rng uses the
+// global generator. It panics if the sampler is empty.
+func (s *Sampler[T]) Sample(rng *rand.Rand) T {
+	if len(s.keys) == 0 {
+		panic("counter: Sample from empty Sampler")
+	}
+	var (
+		i int
+		u float64
+	)
+	if rng == nil {
+		i, u = rand.IntN(len(s.keys)), rand.Float64()
+	} else {
+		i, u = rng.IntN(len(s.keys)), rng.Float64()
+	}
+	if u < s.prob[i] {
+		return s.keys[i]
+	}
+	return s.keys[s.alias[i]]
+}
+
+// SampleN draws n keys. With replacement every draw is independent; without
+// replacement it behaves like random.sample(list(c.elements()), n) in Python,
+// so a key appears at most as many times as its count. It returns
+// ErrSampleSize if n exceeds Total without replacement, or if the sampler is
+// empty and n > 0.
+func (s *Sampler[T]) SampleN(rng *rand.Rand, n int, replace bool) ([]T, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	if s.total == 0 || (!replace && int64(n) > s.total) {
+		return nil, ErrSampleSize
+	}
+	out := make([]T, n)
+	if replace {
+		for i := range out {
+			out[i] = s.Sample(rng)
+		}
+		return out, nil
+	}
+
+	// Without replacement: a Fenwick tree over the remaining weights finds
+	// the key owning a uniform position in O(log Len).
+	tree := newFenwick(s.weights)
+	remaining := s.total
+	for i := range out {
+		var r int64
+		if rng == nil {
+			r = rand.Int64N(remaining)
+		} else {
+			r = rng.Int64N(remaining)
+		}
+		j := tree.find(r)
+		out[i] = s.keys[j]
+		tree.add(j, -1)
+		remaining--
+	}
+	return out, nil
+}
+
+// fenwick is a binary indexed tree of int64 weights.
+type fenwick []int64
+
+func newFenwick(ws []int64) fenwick {
+	f := make(fenwick, len(ws)+1)
+	for i, w := range ws {
+		f.add(i, w)
+	}
+	return f
+}
+
+// add adds d to the weight at index i (0-based).
+func (f fenwick) add(i int, d int64) {
+	for i++; i < len(f); i += i & -i {
+		f[i] += d
+	}
+}
+
+// find returns the smallest index whose prefix sum exceeds r.
+func (f fenwick) find(r int64) int {
+	pos := 0
+	step := 1
+	for step*2 < len(f) {
+		step *= 2
+	}
+	for ; step > 0; step /= 2 {
+		if next := pos + step; next < len(f) && f[next] <= r {
+			pos = next
+			r -= f[next]
+		}
+	}
+	return pos
+}
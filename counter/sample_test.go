@@ -0,0 +1,85 @@
+package counter_test
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestSamplerDistribution(t *testing.T) {
+	c := counter.Counter[string]{"a": 1, "b": 2, "c": 7, "neg": -5, "zero": 0}
+	s := counter.NewSampler(c)
+	assertEqual(t, s.Len(), 3)
+	assertEqual(t, s.Total(), int64(10))
+
+	const N = 100000
+	got := counter.New[string]()
+	rng := counter.SeededRand(7)
+	for i := 0; i < N; i++ {
+		got.Add(s.Sample(rng))
+	}
+	for k, w := range map[string]float64{"a": 0.1, "b": 0.2, "c": 0.7} {
+		freq := float64(got.Get(k)) / N
+		if math.Abs(freq-w) > 0.01 {
+			t.Fatalf("key %s: freq %.4f want %.2f", k, freq, w)
+		}
+	}
+	assertEqual(t, got.Get("neg")+got.Get("zero"), int64(0))
+}
+
+func TestSamplerSeededDeterministic(t *testing.T) {
+	c := counter.FromSlice([]int{1, 2, 2, 3, 3, 3, 4, 4, 4, 4})
+	draw := func() []int {
+		out, err := counter.NewSampler(c.Clone()).SampleN(counter.SeededRand(42), 20, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	first := draw()
+	for i := 0; i < 5; i++ {
+		assertSliceEq(t, draw(), first)
+	}
+}
+
+func TestSamplerWithoutReplacement(t *testing.T) {
+	c := counter.Counter[string]{"a": 3, "b": 1, "c": 2}
+	s := counter.NewSampler(c)
+
+	// 抽完整个袋子，应恰好得到 Elements()
+	all, err := s.SampleN(counter.SeededRand(1), 6, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(all)
+	assertSliceEq(t, all, []string{"a", "a", "a", "b", "c", "c"})
+
+	part, err := s.SampleN(nil, 4, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drawn := counter.FromSlice(part)
+	assertEqual(t, drawn.IsSubset(c), true)
+
+	if _, err := s.SampleN(nil, 7, false); !errors.Is(err, counter.ErrSampleSize) {
+		t.Fatalf("want ErrSampleSize, got %v", err)
+	}
+	if _, err := counter.NewSampler(counter.New[int]()).SampleN(nil, 1, true); !errors.Is(err, counter.ErrSampleSize) {
+		t.Fatalf("want ErrSampleSize for empty sampler, got %v", err)
+	}
+}
+
+func TestSamplerHugeCountsNoMaterialize(t *testing.T) {
+	s := counter.NewSampler(counter.Counter[string]{"x": 1 << 50, "y": 1 << 50})
+	out, err := s.SampleN(counter.SeededRand(3), 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := counter.FromSlice(out)
+	if got.Get("x") < 400 || got.Get("y") < 400 {
+		t.Fatalf("unbalanced draw: %v", got)
+	}
+}
@@ -0,0 +1,311 @@
+package counter
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+//// Lock-striped variant ///////////////////////////////////////////////////////
+
+// ShardedCounter spreads keys across independently locked Counter shards, so
+// writers touching different keys rarely contend. It exposes the same counting
+// API as SafeCounter; whole-counter reads (Items, MostCommon, Total, ...) merge
+// the shards one at a time and therefore are not an atomic snapshot while
+// writers are active.
+type ShardedCounter[T comparable] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []shard[T]
+}
+
+type shard[T comparable] struct {
+	mu sync.RWMutex
+	c  Counter[T]
+	_  [32]byte // pad to a 64-byte cache line (RWMutex 24 + map 8) against false sharing
+}
+
+// NewSharded creates a ShardedCounter with n shards, rounded up to a power of
+// two. If n <= 0, a default based on GOMAXPROCS is used.
+func NewSharded[T comparable](n int) *ShardedCounter[T] {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	n = 1 << bits.Len(uint(n-1))
+	s := &ShardedCounter[T]{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(n - 1),
+		shards: make([]shard[T], n),
+	}
+	for i := range s.shards {
+		s.shards[i].c = New[T]()
+	}
+	return s
+}
+
+func (s *ShardedCounter[T]) index(k T) int {
+	return int(maphash.Comparable(s.seed, k) & s.mask)
+}
+
+func (s *ShardedCounter[T]) shardFor(k T) *shard[T] { return &s.shards[s.index(k)] }
+
+// Shards returns the number of shards.
+func (s *ShardedCounter[T]) Shards() int { return len(s.shards) }
+
+// The methods below mirror SafeCounter's API.
+
+func (s *ShardedCounter[T]) Get(k T) int64 {
+	sh := s.shardFor(k)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.c[k]
+}
+func (s *ShardedCounter[T]) Set(k T, n int64) {
+	sh := s.shardFor(k)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.c.Set(k, n)
+}
+func (s *ShardedCounter[T]) Add(k T) {
+	sh := s.shardFor(k)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.c.Add(k)
+}
+func (s *ShardedCounter[T]) AddN(k T, n int64) {
+	sh := s.shardFor(k)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.c.AddN(k, n)
+}
+func (s *ShardedCounter[T]) Delete(k T) int64 {
+	sh := s.shardFor(k)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.c.Delete(k)
+}
+
+// split partitions other by shard so each shard lock is taken once.
+func (s *ShardedCounter[T]) split(other Counter[T]) []Counter[T] {
+	parts := make([]Counter[T], len(s.shards))
+	for k, v := range other {
+		i := s.index(k)
+		if parts[i] == nil {
+			parts[i] = New[T]()
+		}
+		parts[i][k] = v
+	}
+	return parts
+}
+
+// eachPart applies op to every shard that has a non-empty part.
+func (s *ShardedCounter[T]) eachPart(parts []Counter[T], op func(c, part Counter[T])) {
+	for i, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		op(sh.c, part)
+		sh.mu.Unlock()
+	}
+}
+
+func (s *ShardedCounter[T]) Update(other Counter[T]) {
+	s.eachPart(s.split(other), Counter[T].Update)
+}
+func (s *ShardedCounter[T]) UpdateSlice(xs []T) {
+	parts := make([]Counter[T], len(s.shards))
+	for _, x := range xs {
+		i := s.index(x)
+		if parts[i] == nil {
+			parts[i] = New[T]()
+		}
+		parts[i][x]++
+	}
+	s.eachPart(parts, Counter[T].Update)
+}
+func (s *ShardedCounter[T]) Subtract(other Counter[T]) {
+	s.eachPart(s.split(other), Counter[T].Subtract)
+}
+func (s *ShardedCounter[T]) Clean() {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		sh.c.Clean()
+		sh.mu.Unlock()
+	}
+}
+
+// read calls fn with each shard's Counter under that shard's read lock.
+func (s *ShardedCounter[T]) read(fn func(c Counter[T])) {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.RLock()
+		fn(sh.c)
+		sh.mu.RUnlock()
+	}
+}
+
+func (s *ShardedCounter[T]) Total() int64 {
+	var n int64
+	s.read(func(c Counter[T]) { n += c.Total() })
+	return n
+}
+func (s *ShardedCounter[T]) TotalRaw() int64 {
+	var n int64
+	s.read(func(c Counter[T]) { n += c.TotalRaw() })
+	return n
+}
+
+// Len returns the number of distinct keys.
+func (s *ShardedCounter[T]) Len() int {
+	n := 0
+	s.read(func(c Counter[T]) { n += len(c) })
+	return n
+}
+func (s *ShardedCounter[T]) Keys() []T {
+	var out []T
+	s.read(func(c Counter[T]) {
+		for k := range c {
+			out = append(out, k)
+		}
+	})
+	return out
+}
+func (s *ShardedCounter[T]) Values() []int64 {
+	var out []int64
+	s.read(func(c Counter[T]) {
+		for _, v := range c {
+			out = append(out, v)
+		}
+	})
+	return out
+}
+func (s *ShardedCounter[T]) Items() []Pair[T] {
+	var out []Pair[T]
+	s.read(func(c Counter[T]) {
+		for k, v := range c {
+			out = append(out, Pair[T]{k, v})
+		}
+	})
+	return out
+}
+
+// Clone merges all shards into a plain Counter. Use it to apply the
+// arithmetic and comparison helpers defined on Counter.
+func (s *ShardedCounter[T]) Clone() Counter[T] {
+	out := New[T]()
+	s.read(func(c Counter[T]) {
+		for k, v := range c {
+			out[k] = v
+		}
+	})
+	return out
+}
+func (s *ShardedCounter[T]) MostCommon(n int) []Pair[T] {
+	return s.Clone().MostCommon(n)
+}
+func (s *ShardedCounter[T]) MostCommonFunc(n int, cmp func(a, b T) int) []Pair[T] {
+	return s.Clone().MostCommonFunc(n, cmp)
+}
+func (s *ShardedCounter[T]) Elements() []T {
+	return s.Clone().Elements()
+}
+
+// MarshalJSON encodes the merged shards, see Counter.MarshalJSON.
+func (s *ShardedCounter[T]) MarshalJSON() ([]byte, error) {
+	return s.Clone().MarshalJSON()
+}
+
+// UnmarshalJSON decodes into the counter, see Counter.UnmarshalJSON. Like
+// SafeCounter.UnmarshalJSON it sets the decoded keys and leaves the others
+// alone. The input is fully decoded before any shard lock is taken.
+func (s *ShardedCounter[T]) UnmarshalJSON(data []byte) error {
+	var in Counter[T]
+	if err := in.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	s.eachPart(s.split(in), setAll[T])
+	return nil
+}
+
+// setAll copies every count of part into c.
+func setAll[T comparable](c, part Counter[T]) {
+	for k, v := range part {
+		c[k] = v
+	}
+}
+
+// Arithmetic and comparisons mirror SafeCounter's. Each operand is read
+// through Clone, so with concurrent writers the operands are not atomic
+// snapshots (see ShardedCounter).
+
+// binary applies op to merged copies of s and other and returns the result
+// in a new ShardedCounter with as many shards as s.
+func (s *ShardedCounter[T]) binary(other *ShardedCounter[T], op func(a, b Counter[T]) Counter[T]) *ShardedCounter[T] {
+	return s.from(op(s.Clone(), other.Clone()))
+}
+
+// from returns a ShardedCounter with as many shards as s holding c.
+func (s *ShardedCounter[T]) from(c Counter[T]) *ShardedCounter[T] {
+	out := NewSharded[T](len(s.shards))
+	out.eachPart(out.split(c), setAll[T])
+	return out
+}
+
+// Plus is the sharded counterpart of Counter.Plus.
+func (s *ShardedCounter[T]) Plus(other *ShardedCounter[T]) *ShardedCounter[T] {
+	return s.binary(other, Counter[T].Plus)
+}
+
+// Minus is the sharded counterpart of Counter.Minus.
+func (s *ShardedCounter[T]) Minus(other *ShardedCounter[T]) *ShardedCounter[T] {
+	return s.binary(other, Counter[T].Minus)
+}
+
+// Union is the sharded counterpart of Counter.Union.
+func (s *ShardedCounter[T]) Union(other *ShardedCounter[T]) *ShardedCounter[T] {
+	return s.binary(other, Counter[T].Union)
+}
+
+// Intersect is the sharded counterpart of Counter.Intersect.
+func (s *ShardedCounter[T]) Intersect(other *ShardedCounter[T]) *ShardedCounter[T] {
+	return s.binary(other, Counter[T].Intersect)
+}
+
+// Positive is the sharded counterpart of Counter.Positive.
+func (s *ShardedCounter[T]) Positive() *ShardedCounter[T] {
+	return s.from(s.Clone().Positive())
+}
+
+// Negative is the sharded counterpart of Counter.Negative.
+func (s *ShardedCounter[T]) Negative() *ShardedCounter[T] {
+	return s.from(s.Clone().Negative())
+}
+
+// Equal is the sharded counterpart of Counter.Equal.
+func (s *ShardedCounter[T]) Equal(other *ShardedCounter[T]) bool {
+	return s.Clone().Equal(other.Clone())
+}
+
+// IsSubset is the sharded counterpart of Counter.IsSubset.
+func (s *ShardedCounter[T]) IsSubset(other *ShardedCounter[T]) bool {
+	return s.Clone().IsSubset(other.Clone())
+}
+
+// IsProperSubset is the sharded counterpart of Counter.IsProperSubset.
+func (s *ShardedCounter[T]) IsProperSubset(other *ShardedCounter[T]) bool {
+	return s.Clone().IsProperSubset(other.Clone())
+}
+
+// IsSuperset is the sharded counterpart of Counter.IsSuperset.
+func (s *ShardedCounter[T]) IsSuperset(other *ShardedCounter[T]) bool {
+	return s.Clone().IsSuperset(other.Clone())
+}
+
+// IsProperSuperset is the sharded counterpart of Counter.IsProperSuperset.
+func (s *ShardedCounter[T]) IsProperSuperset(other *ShardedCounter[T]) bool {
+	return s.Clone().IsProperSuperset(other.Clone())
+}
@@ -0,0 +1,135 @@
+package counter_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestShardedMatchesCounter(t *testing.T) {
+	sc := counter.NewSharded[string](5)
+	assertEqual(t, sc.Shards(), 8)
+
+	want := counter.New[string]()
+	words := []string{"a", "b", "a", "c", "d", "a", "e", "f", "g", "b"}
+	sc.UpdateSlice(words)
+	want.UpdateSlice(words)
+	sc.Update(counter.Counter[string]{"x": 3, "a": -1})
+	want.Update(counter.Counter[string]{"x": 3, "a": -1})
+	sc.Subtract(counter.Counter[string]{"b": 2})
+	want.Subtract(counter.Counter[string]{"b": 2})
+	sc.AddN("y", 2)
+	want.AddN("y", 2)
+	sc.Set("z", 0)
+	sc.Clean()
+
+	assertEqual(t, sc.Clone().Equal(want), true)
+	assertEqual(t, sc.Len(), len(want))
+	assertEqual(t, sc.Total(), want.Total())
+	assertEqual(t, sc.TotalRaw(), want.TotalRaw())
+	assertEqual(t, len(sc.Keys()), len(want))
+	assertEqual(t, len(sc.Values()), len(want))
+	assertEqual(t, len(sc.Items()), len(want))
+	assertEqual(t, len(sc.Elements()), int(want.Total()))
+	assertEqual(t, fmt.Sprint(sc.MostCommon(3)), fmt.Sprint(want.MostCommon(3)))
+	assertEqual(t, sc.Delete("x"), int64(3))
+	assertEqual(t, sc.Get("x"), int64(0))
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), `{"a":2,"c":1,"d":1,"e":1,"f":1,"g":1,"y":2}`)
+
+	back := counter.NewSharded[string](2)
+	if err := json.Unmarshal(data, back); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, back.Total(), int64(9))
+	assertEqual(t, back.Clone().Equal(sc.Clone()), true)
+	if err := json.Unmarshal([]byte(`[[1,2]]`), counter.NewSharded[string](2)); err == nil {
+		t.Fatal("want error for mismatched key type")
+	}
+}
+
+func TestShardedArithmeticAndCompare(t *testing.T) {
+	a := counter.NewSharded[string](4)
+	a.Update(counter.Counter[string]{"x": 3, "y": 1, "n": -2})
+	b := counter.NewSharded[string](2)
+	b.Update(counter.Counter[string]{"x": 1, "y": 4})
+	ca, cb := a.Clone(), b.Clone()
+
+	// 与 Counter 的同名运算结果一致，且沿用 a 的分片数
+	for name, got := range map[string]*counter.ShardedCounter[string]{
+		"plus":      a.Plus(b),
+		"minus":     a.Minus(b),
+		"union":     a.Union(b),
+		"intersect": a.Intersect(b),
+		"positive":  a.Positive(),
+		"negative":  a.Negative(),
+	} {
+		want := map[string]counter.Counter[string]{
+			"plus":      ca.Plus(cb),
+			"minus":     ca.Minus(cb),
+			"union":     ca.Union(cb),
+			"intersect": ca.Intersect(cb),
+			"positive":  ca.Positive(),
+			"negative":  ca.Negative(),
+		}[name]
+		if !got.Clone().Equal(want) || got.Shards() != a.Shards() {
+			t.Fatalf("%s: got %v (%d shards), want %v", name, got.Clone(), got.Shards(), want)
+		}
+	}
+
+	assertEqual(t, a.Equal(b), false)
+	assertEqual(t, a.Positive().IsSubset(a.Union(b)), true)
+	assertEqual(t, a.Positive().IsProperSubset(a.Union(b)), true)
+	assertEqual(t, a.Union(b).IsSuperset(b), true)
+	assertEqual(t, b.IsProperSuperset(b), false)
+	assertEqual(t, a.Plus(b).Equal(b.Plus(a)), true)
+}
+
+func TestShardedConcurrentAdd(t *testing.T) {
+	sc := counter.NewSharded[int](0)
+	const G, N = 16, 2000
+	var wg sync.WaitGroup
+	wg.Add(G)
+	for g := 0; g < G; g++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < N; i++ {
+				sc.Add(i % 100)
+			}
+		}()
+	}
+	wg.Wait()
+	assertEqual(t, sc.Total(), int64(G*N))
+	assertEqual(t, sc.Get(7), int64(G*N/100))
+}
+
+// 并行压测：单锁 SafeCounter vs 分片 ShardedCounter
+
+func BenchmarkSafeCounter_AddParallel(b *testing.B) {
+	sc := counter.NewSafe[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			sc.Add(i & 1023)
+			i++
+		}
+	})
+}
+
+func BenchmarkShardedCounter_AddParallel(b *testing.B) {
+	sc := counter.NewSharded[int](0)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			sc.Add(i & 1023)
+			i++
+		}
+	})
+}
@@ -0,0 +1,133 @@
+// Package sketch holds probabilistic, bounded-memory companions to
+// counter.Counter for streams that are too large to count exactly.
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"math"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// ErrIncompatible is returned by Merge when two sketches cannot be combined.
+var ErrIncompatible = errors.New("sketch: incompatible sketches")
+
+// CountMin is a Count-Min Sketch with conservative update.
+// It uses the same method names as counter.Counter (Add, AddN, Get, Total),
+// so it can stand in for one when only point estimates are needed.
+//
+// Get never underestimates; with probability 1-delta it overestimates by at
+// most epsilon*Total().
+type CountMin[T comparable] struct {
+	seed  maphash.Seed
+	width uint64
+	depth int
+	cells []int64 // depth rows of width cells
+	total int64
+}
+
+// NewCountMin creates a sketch with error factor epsilon and failure
+// probability delta, both in (0, 1). It panics on invalid parameters.
+func NewCountMin[T comparable](epsilon, delta float64) *CountMin[T] {
+	if !(epsilon > 0 && epsilon < 1) || !(delta > 0 && delta < 1) {
+		panic(fmt.Sprintf("sketch: invalid epsilon=%v delta=%v", epsilon, delta))
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return &CountMin[T]{
+		seed:  maphash.MakeSeed(),
+		width: width,
+		depth: depth,
+		cells: make([]int64, uint64(depth)*width),
+	}
+}
+
+// CountMinFromCounter seeds a new sketch with the positive counts of c.
+func CountMinFromCounter[T comparable](c counter.Counter[T], epsilon, delta float64) *CountMin[T] {
+	s := NewCountMin[T](epsilon, delta)
+	for k, v := range c {
+		if v > 0 {
+			s.AddN(k, v)
+		}
+	}
+	return s
+}
+
+// Empty returns an empty sketch with the same dimensions and hash seed.
+// Only sketches derived from one another this way can be merged.
+func (s *CountMin[T]) Empty() *CountMin[T] {
+	return &CountMin[T]{
+		seed:  s.seed,
+		width: s.width,
+		depth: s.depth,
+		cells: make([]int64, len(s.cells)),
+	}
+}
+
+// Width returns the number of cells per row.
+func (s *CountMin[T]) Width() int { return int(s.width) }
+
+// Depth returns the number of rows (hash functions).
+func (s *CountMin[T]) Depth() int { return s.depth }
+
+// index returns the cell offset of k in row i, using double hashing to
+// derive depth independent-enough positions from one 64-bit hash.
+func (s *CountMin[T]) index(h uint64, i int) uint64 {
+	h1, h2 := h&0xffffffff, h>>32|1
+	return uint64(i)*s.width + (h1+uint64(i)*h2)%s.width
+}
+
+// Add increments key by 1.
+func (s *CountMin[T]) Add(k T) { s.AddN(k, 1) }
+
+// AddN increments key by n using conservative update: only the cells that
+// hold the current minimum are raised. Sketches cannot forget, so AddN
+// panics if n is negative; n == 0 is a no-op.
+func (s *CountMin[T]) AddN(k T, n int64) {
+	if n < 0 {
+		panic("sketch: CountMin.AddN with negative count")
+	}
+	if n == 0 {
+		return
+	}
+	h := maphash.Comparable(s.seed, k)
+	target := s.estimate(h) + n
+	for i := 0; i < s.depth; i++ {
+		j := s.index(h, i)
+		if s.cells[j] < target {
+			s.cells[j] = target
+		}
+	}
+	s.total += n
+}
+
+// Get returns the estimated count for key (never less than the true count).
+func (s *CountMin[T]) Get(k T) int64 {
+	return s.estimate(maphash.Comparable(s.seed, k))
+}
+
+func (s *CountMin[T]) estimate(h uint64) int64 {
+	est := int64(math.MaxInt64)
+	for i := 0; i < s.depth; i++ {
+		est = min(est, s.cells[s.index(h, i)])
+	}
+	return est
+}
+
+// Total returns the exact sum of all counts added.
+func (s *CountMin[T]) Total() int64 { return s.total }
+
+// Merge adds other into s. Both sketches must come from the same NewCountMin
+// call (see Empty); otherwise ErrIncompatible is returned and s is unchanged.
+func (s *CountMin[T]) Merge(other *CountMin[T]) error {
+	if s.seed != other.seed || s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+	for i, v := range other.cells {
+		s.cells[i] += v
+	}
+	s.total += other.total
+	return nil
+}
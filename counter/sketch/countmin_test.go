@@ -0,0 +1,92 @@
+package sketch_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/sketch"
+)
+
+// pointCounter is the subset of counter.Counter's API that CountMin shares.
+type pointCounter[T comparable] interface {
+	Add(k T)
+	AddN(k T, n int64)
+	Get(k T) int64
+	Total() int64
+}
+
+var (
+	_ pointCounter[string] = counter.Counter[string]{}
+	_ pointCounter[string] = (*sketch.CountMin[string])(nil)
+)
+
+func zipfStream(seed int64, n int) []uint64 {
+	rng := rand.New(rand.NewSource(seed))
+	z := rand.NewZipf(rng, 1.2, 1, 100000)
+	out := make([]uint64, n)
+	for i := range out {
+		out[i] = z.Uint64()
+	}
+	return out
+}
+
+func TestCountMinErrorBound(t *testing.T) {
+	const eps, delta = 0.001, 0.01
+	exact := counter.New[uint64]()
+	var cm pointCounter[uint64] = sketch.NewCountMin[uint64](eps, delta)
+	for _, x := range zipfStream(1, 200000) {
+		exact.Add(x)
+		cm.Add(x)
+	}
+	if cm.Total() != exact.Total() {
+		t.Fatalf("total %d != %d", cm.Total(), exact.Total())
+	}
+
+	bound := int64(eps * float64(exact.Total()))
+	misses := 0
+	for k, v := range exact {
+		est := cm.Get(k)
+		if est < v {
+			t.Fatalf("key %d underestimated: %d < %d", k, est, v)
+		}
+		if est-v > bound {
+			misses++
+		}
+	}
+	if float64(misses) > delta*float64(len(exact)) {
+		t.Fatalf("%d of %d keys exceed the eps bound", misses, len(exact))
+	}
+}
+
+func TestCountMinFromCounterAndMerge(t *testing.T) {
+	c := counter.Counter[string]{"a": 5, "b": 2, "neg": -3}
+	s := sketch.CountMinFromCounter(c, 0.01, 0.01)
+	if s.Get("a") < 5 || s.Get("b") < 2 || s.Total() != 7 {
+		t.Fatalf("bad seed: a=%d b=%d total=%d", s.Get("a"), s.Get("b"), s.Total())
+	}
+
+	w := s.Empty()
+	w.AddN("a", 10)
+	if err := s.Merge(w); err != nil {
+		t.Fatal(err)
+	}
+	if s.Get("a") < 15 || s.Total() != 17 {
+		t.Fatalf("after merge a=%d total=%d", s.Get("a"), s.Total())
+	}
+
+	other := sketch.NewCountMin[string](0.01, 0.01)
+	if err := s.Merge(other); !errors.Is(err, sketch.ErrIncompatible) {
+		t.Fatalf("want ErrIncompatible, got %v", err)
+	}
+}
+
+func TestCountMinNegativePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	sketch.NewCountMin[int](0.1, 0.1).AddN(1, -1)
+}
@@ -0,0 +1,180 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// HyperLogLog estimates the number of distinct keys in a stream, i.e.
+// len(counter.Counter[T]) without storing the keys. With precision p it uses
+// 2^p one-byte registers and has a standard error of about 1.04/sqrt(2^p).
+//
+// Register state is portable: MarshalBinary output can be loaded and merged
+// in another process, because the default hash is stable across runs (see
+// NewHyperLogLog). Sketches must share precision and hash to be merged.
+type HyperLogLog[T comparable] struct {
+	p    uint8
+	regs []uint8
+	hash func(T) uint64
+}
+
+// Precision limits for HyperLogLog.
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+)
+
+// hllVersion is the first byte of the binary encoding.
+const hllVersion = 1
+
+// NewHyperLogLog creates an estimator with 2^precision registers.
+// Strings, integers and floats are hashed with a stable 64-bit hash; other
+// key types hash their %#v representation, which is stable only for values
+// without pointers. Use NewHyperLogLogFunc to supply a hash for such keys.
+// It panics if precision is outside [MinPrecision, MaxPrecision].
+func NewHyperLogLog[T comparable](precision int) *HyperLogLog[T] {
+	return NewHyperLogLogFunc(precision, stableHash[T]())
+}
+
+// NewHyperLogLogFunc is NewHyperLogLog with a caller-supplied 64-bit hash.
+func NewHyperLogLogFunc[T comparable](precision int, hash func(T) uint64) *HyperLogLog[T] {
+	if precision < MinPrecision || precision > MaxPrecision {
+		panic(fmt.Sprintf("sketch: invalid HyperLogLog precision %d", precision))
+	}
+	return &HyperLogLog[T]{
+		p:    uint8(precision),
+		regs: make([]uint8, 1<<precision),
+		hash: hash,
+	}
+}
+
+// Precision returns the number of index bits.
+func (h *HyperLogLog[T]) Precision() int { return int(h.p) }
+
+// Add records one occurrence of k.
+func (h *HyperLogLog[T]) Add(k T) {
+	x := h.hash(k)
+	i := x >> (64 - h.p)
+	// Sentinel bit caps the rank at 64-p+1 when the remaining bits are zero.
+	w := x<<h.p | 1<<(h.p-1)
+	if r := uint8(bits.LeadingZeros64(w) + 1); r > h.regs[i] {
+		h.regs[i] = r
+	}
+}
+
+// Estimate returns the estimated number of distinct keys added.
+func (h *HyperLogLog[T]) Estimate() uint64 {
+	m := float64(len(h.regs))
+	var sum float64
+	zeros := 0
+	for _, r := range h.regs {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	est := alpha(len(h.regs)) * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		// Small-range correction: linear counting is more accurate here.
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge folds other into h, so h estimates the union of both streams.
+// It returns ErrIncompatible if the precisions differ.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.p != other.p {
+		return ErrIncompatible
+	}
+	for i, r := range other.regs {
+		h.regs[i] = max(h.regs[i], r)
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The layout is one version byte, one precision byte and the registers.
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, 2+len(h.regs))
+	out = append(out, hllVersion, h.p)
+	return append(out, h.regs...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It may be called on
+// a zero HyperLogLog, which then uses the default stable hash.
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != hllVersion {
+		return errors.New("sketch: bad HyperLogLog encoding")
+	}
+	p := int(data[1])
+	if p < MinPrecision || p > MaxPrecision || len(data) != 2+1<<p {
+		return fmt.Errorf("sketch: bad HyperLogLog precision %d or length %d", p, len(data))
+	}
+	for _, r := range data[2:] {
+		if int(r) > 64-p+1 {
+			return fmt.Errorf("sketch: bad HyperLogLog register %d", r)
+		}
+	}
+	h.p = uint8(p)
+	h.regs = append(h.regs[:0], data[2:]...)
+	if h.hash == nil {
+		h.hash = stableHash[T]()
+	}
+	return nil
+}
+
+// stableHash returns a hash of T that does not depend on a per-process seed.
+func stableHash[T comparable]() func(T) uint64 {
+	switch any(*new(T)).(type) {
+	case string:
+		return func(k T) uint64 { return hashString(any(k).(string)) }
+	case int:
+		return func(k T) uint64 { return fmix64(uint64(any(k).(int))) }
+	case int32:
+		return func(k T) uint64 { return fmix64(uint64(any(k).(int32))) }
+	case int64:
+		return func(k T) uint64 { return fmix64(uint64(any(k).(int64))) }
+	case uint:
+		return func(k T) uint64 { return fmix64(uint64(any(k).(uint))) }
+	case uint32:
+		return func(k T) uint64 { return fmix64(uint64(any(k).(uint32))) }
+	case uint64:
+		return func(k T) uint64 { return fmix64(any(k).(uint64)) }
+	case float64:
+		return func(k T) uint64 { return fmix64(math.Float64bits(any(k).(float64))) }
+	}
+	return func(k T) uint64 { return hashString(fmt.Sprintf("%#v", k)) }
+}
+
+func hashString(s string) uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(s))
+	return fmix64(f.Sum64())
+}
+
+// fmix64 is the MurmurHash3 finalizer; it spreads every input bit over the
+// whole word, which HyperLogLog relies on for its leading-zero counts.
+func fmix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,96 @@
+package sketch_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/sketch"
+)
+
+func relErr(est uint64, exact int) float64 {
+	return math.Abs(float64(est)-float64(exact)) / float64(exact)
+}
+
+func TestHyperLogLogAccuracy(t *testing.T) {
+	for _, p := range []int{10, 14} {
+		stdErr := 1.04 / math.Sqrt(float64(int(1)<<p))
+		for _, n := range []int{100, 5000, 200000} {
+			t.Run(fmt.Sprintf("p=%d/n=%d", p, n), func(t *testing.T) {
+				rng := rand.New(rand.NewSource(int64(n + p)))
+				exact := counter.New[string]()
+				hll := sketch.NewHyperLogLog[string](p)
+				for i := 0; i < 2*n && len(exact) < n; i++ {
+					k := fmt.Sprintf("user-%d", rng.Intn(n*4))
+					exact.Add(k)
+					hll.Add(k)
+				}
+				if e := relErr(hll.Estimate(), len(exact)); e > 4*stdErr {
+					t.Fatalf("estimate %d vs exact %d: rel err %.4f > %.4f",
+						hll.Estimate(), len(exact), e, 4*stdErr)
+				}
+			})
+		}
+	}
+}
+
+func TestHyperLogLogIntKeysAndMerge(t *testing.T) {
+	exact := counter.New[int]()
+	a, b := sketch.NewHyperLogLog[int](12), sketch.NewHyperLogLog[int](12)
+	whole := sketch.NewHyperLogLog[int](12)
+	for i := 0; i < 50000; i++ {
+		k := i * 7919 % 30011
+		exact.Add(k)
+		whole.Add(k)
+		if i%2 == 0 {
+			a.Add(k)
+		} else {
+			b.Add(k)
+		}
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Estimate() != whole.Estimate() {
+		t.Fatalf("merged %d != whole %d", a.Estimate(), whole.Estimate())
+	}
+	if e := relErr(a.Estimate(), len(exact)); e > 0.05 {
+		t.Fatalf("estimate %d vs %d", a.Estimate(), len(exact))
+	}
+	if err := a.Merge(sketch.NewHyperLogLog[int](10)); !errors.Is(err, sketch.ErrIncompatible) {
+		t.Fatalf("want ErrIncompatible, got %v", err)
+	}
+}
+
+func TestHyperLogLogBinaryRoundTrip(t *testing.T) {
+	h := sketch.NewHyperLogLog[string](8)
+	for i := 0; i < 1000; i++ {
+		h.Add(fmt.Sprint(i))
+	}
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back sketch.HyperLogLog[string]
+	if err := back.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if back.Estimate() != h.Estimate() || back.Precision() != 8 {
+		t.Fatalf("round trip: %d != %d", back.Estimate(), h.Estimate())
+	}
+	// 反序列化后继续添加已有的键，估计值不应变化（哈希跨进程稳定）
+	back.Add("1")
+	if back.Estimate() != h.Estimate() {
+		t.Fatalf("re-adding a known key changed the estimate")
+	}
+
+	for _, bad := range [][]byte{nil, {9, 8}, data[:10], append([]byte{1, 3}, make([]byte, 8)...)} {
+		if err := back.UnmarshalBinary(bad); err == nil {
+			t.Fatalf("expected error for %v", bad)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package sketch
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// SpaceSaving tracks the most frequent keys of a stream in bounded memory
+// using the Space-Saving algorithm (Metwally et al.). At most Capacity keys
+// are kept; when a new key arrives and the summary is full, it replaces the
+// key with the smallest count and inherits that count as its error.
+//
+// Every key whose true count exceeds Total()/Capacity() is guaranteed to be
+// tracked, and each reported count overestimates the truth by at most Err.
+type SpaceSaving[T comparable] struct {
+	capacity int
+	total    int64
+	index    map[T]*ssEntry[T]
+	heap     ssHeap[T]
+}
+
+// HeavyHitter is a MostCommon entry of a SpaceSaving summary.
+// The true count of Key lies in [Count-Err, Count].
+type HeavyHitter[T comparable] struct {
+	counter.Pair[T]
+	Err int64
+}
+
+type ssEntry[T comparable] struct {
+	key   T
+	count int64
+	err   int64
+	pos   int
+}
+
+// NewSpaceSaving creates a summary that tracks up to capacity keys.
+// It panics if capacity <= 0.
+func NewSpaceSaving[T comparable](capacity int) *SpaceSaving[T] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("sketch: invalid SpaceSaving capacity %d", capacity))
+	}
+	return &SpaceSaving[T]{
+		capacity: capacity,
+		index:    make(map[T]*ssEntry[T], capacity),
+		heap:     make(ssHeap[T], 0, capacity),
+	}
+}
+
+// Capacity returns the maximum number of tracked keys.
+func (s *SpaceSaving[T]) Capacity() int { return s.capacity }
+
+// Len returns the number of currently tracked keys.
+func (s *SpaceSaving[T]) Len() int { return len(s.heap) }
+
+// Total returns the exact sum of all counts added.
+func (s *SpaceSaving[T]) Total() int64 { return s.total }
+
+// Add counts one occurrence of k.
+func (s *SpaceSaving[T]) Add(k T) { s.AddN(k, 1) }
+
+// AddN counts n occurrences of k. It panics if n is negative.
+func (s *SpaceSaving[T]) AddN(k T, n int64) {
+	if n < 0 {
+		panic("sketch: SpaceSaving.AddN with negative count")
+	}
+	if n == 0 {
+		return
+	}
+	s.total += n
+	if e, ok := s.index[k]; ok {
+		e.count += n
+		heap.Fix(&s.heap, e.pos)
+		return
+	}
+	if len(s.heap) < s.capacity {
+		e := &ssEntry[T]{key: k, count: n}
+		s.index[k] = e
+		heap.Push(&s.heap, e)
+		return
+	}
+	// Evict the minimum and let k inherit its count as error.
+	e := s.heap[0]
+	delete(s.index, e.key)
+	e.key, e.err, e.count = k, e.count, e.count+n
+	s.index[k] = e
+	heap.Fix(&s.heap, 0)
+}
+
+// floor is the count an untracked key may have at most.
+func (s *SpaceSaving[T]) floor() int64 {
+	if len(s.heap) < s.capacity {
+		return 0
+	}
+	return s.heap[0].count
+}
+
+// Get returns an upper bound on the count of k.
+func (s *SpaceSaving[T]) Get(k T) int64 {
+	if e, ok := s.index[k]; ok {
+		return e.count
+	}
+	return s.floor()
+}
+
+// MostCommon returns up to n tracked keys ordered like counter.Counter's
+// MostCommon, each with its error bound. If n <= 0 every tracked key is
+// returned.
+func (s *SpaceSaving[T]) MostCommon(n int) []HeavyHitter[T] {
+	c := counter.New[T]()
+	for k, e := range s.index {
+		c[k] = e.count
+	}
+	top := c.MostCommon(n)
+	out := make([]HeavyHitter[T], len(top))
+	for i, p := range top {
+		out[i] = HeavyHitter[T]{Pair: p, Err: s.index[p.Key].err}
+	}
+	return out
+}
+
+// Merge folds other into s using the mergeable-summaries rule: a key missing
+// from a full summary is assumed to have that summary's minimum count, which
+// is also added to its error. The capacity of s is kept.
+func (s *SpaceSaving[T]) Merge(other *SpaceSaving[T]) {
+	m1, m2 := s.floor(), other.floor()
+	merged := make(map[T]*ssEntry[T], len(s.index)+len(other.index))
+	for k, e := range s.index {
+		c, r := m2, m2
+		if o, ok := other.index[k]; ok {
+			c, r = o.count, o.err
+		}
+		merged[k] = &ssEntry[T]{key: k, count: e.count + c, err: e.err + r}
+	}
+	for k, o := range other.index {
+		if _, ok := merged[k]; !ok {
+			merged[k] = &ssEntry[T]{key: k, count: o.count + m1, err: o.err + m1}
+		}
+	}
+
+	// Keep the capacity largest entries.
+	s.heap = s.heap[:0]
+	for _, e := range merged {
+		if len(s.heap) < s.capacity {
+			heap.Push(&s.heap, e)
+		} else if e.count > s.heap[0].count {
+			s.heap[0] = e
+			e.pos = 0
+			heap.Fix(&s.heap, 0)
+		}
+	}
+	clear(s.index)
+	for _, e := range s.heap {
+		s.index[e.key] = e
+	}
+	s.total += other.total
+}
+
+// ssHeap is a container/heap min-heap of entries ordered by count.
+type ssHeap[T comparable] []*ssEntry[T]
+
+func (h ssHeap[T]) Len() int           { return len(h) }
+func (h ssHeap[T]) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h ssHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+func (h *ssHeap[T]) Push(x any) {
+	e := x.(*ssEntry[T])
+	e.pos = len(*h)
+	*h = append(*h, e)
+}
+func (h *ssHeap[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,83 @@
+package sketch_test
+
+import (
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/sketch"
+)
+
+func checkBounds(t *testing.T, ss *sketch.SpaceSaving[uint64], exact counter.Counter[uint64]) {
+	t.Helper()
+	if ss.Total() != exact.Total() {
+		t.Fatalf("total %d != %d", ss.Total(), exact.Total())
+	}
+	for _, h := range ss.MostCommon(0) {
+		truth := exact.Get(h.Key)
+		if truth > h.Count || truth < h.Count-h.Err {
+			t.Fatalf("key %d: true %d outside [%d, %d]", h.Key, truth, h.Count-h.Err, h.Count)
+		}
+	}
+	// 频率超过 Total/Capacity 的键一定被追踪
+	threshold := exact.Total() / int64(ss.Capacity())
+	tracked := map[uint64]bool{}
+	for _, h := range ss.MostCommon(0) {
+		tracked[h.Key] = true
+	}
+	for k, v := range exact {
+		if v > threshold && !tracked[k] {
+			t.Fatalf("heavy key %d (count %d > %d) not tracked", k, v, threshold)
+		}
+	}
+}
+
+func TestSpaceSavingExactWhenFits(t *testing.T) {
+	ss := sketch.NewSpaceSaving[string](10)
+	for _, w := range []string{"a", "b", "a", "c", "a", "b"} {
+		ss.Add(w)
+	}
+	got := ss.MostCommon(2)
+	want := []counter.Pair[string]{{Key: "a", Count: 3}, {Key: "b", Count: 2}}
+	for i := range want {
+		if got[i].Pair != want[i] || got[i].Err != 0 {
+			t.Fatalf("idx %d: got %+v want %+v", i, got[i], want[i])
+		}
+	}
+	if ss.Get("zzz") != 0 || ss.Len() != 3 {
+		t.Fatalf("unexpected Get/Len: %d %d", ss.Get("zzz"), ss.Len())
+	}
+}
+
+func TestSpaceSavingBounds(t *testing.T) {
+	ss := sketch.NewSpaceSaving[uint64](100)
+	exact := counter.New[uint64]()
+	for _, x := range zipfStream(2, 100000) {
+		ss.Add(x)
+		exact.Add(x)
+	}
+	if ss.Len() != 100 {
+		t.Fatalf("len %d", ss.Len())
+	}
+	checkBounds(t, ss, exact)
+	if top := ss.MostCommon(1)[0]; top.Key != exact.MostCommon(1)[0].Key {
+		t.Fatalf("top key %d != %d", top.Key, exact.MostCommon(1)[0].Key)
+	}
+}
+
+func TestSpaceSavingMerge(t *testing.T) {
+	stream := zipfStream(3, 120000)
+	exact := counter.New[uint64]()
+	workers := make([]*sketch.SpaceSaving[uint64], 4)
+	for i := range workers {
+		workers[i] = sketch.NewSpaceSaving[uint64](200)
+	}
+	for i, x := range stream {
+		workers[i%len(workers)].Add(x)
+		exact.Add(x)
+	}
+	merged := workers[0]
+	for _, w := range workers[1:] {
+		merged.Merge(w)
+	}
+	checkBounds(t, merged, exact)
+}
@@ -0,0 +1,272 @@
+package counter
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"math/rand/v2"
+)
+
+// SortedCounter is a Counter for cmp.Ordered keys that also answers
+// order-statistic queries: how many elements fall in a key range, the rank
+// of a key and which key holds the i-th element. It is backed by a treap
+// whose nodes carry subtree sums, so every operation is O(log n) expected.
+//
+// Like Total and Elements, the order statistics only see positive counts;
+// zero and negative entries are stored but take up no positions.
+//
+// The zero value is ready to use. SortedCounter is not safe for concurrent
+// use.
+type SortedCounter[T cmp.Ordered] struct {
+	root *sortedNode[T]
+	n    int
+}
+
+type sortedNode[T cmp.Ordered] struct {
+	key         T
+	count       int64
+	sum         int64 // positive counts in this subtree
+	prio        uint64
+	left, right *sortedNode[T]
+}
+
+func (nd *sortedNode[T]) total() int64 {
+	if nd == nil {
+		return 0
+	}
+	return nd.sum
+}
+
+func (nd *sortedNode[T]) fix() {
+	nd.sum = max(nd.count, 0) + nd.left.total() + nd.right.total()
+}
+
+// NewSorted creates an empty SortedCounter.
+func NewSorted[T cmp.Ordered]() *SortedCounter[T] { return &SortedCounter[T]{} }
+
+// SortedFrom builds a SortedCounter holding the entries of c.
+func SortedFrom[T cmp.Ordered](c Counter[T]) *SortedCounter[T] {
+	s := NewSorted[T]()
+	for k, n := range c {
+		s.Set(k, n)
+	}
+	return s
+}
+
+// Get returns count for key (0 if missing).
+func (s *SortedCounter[T]) Get(k T) int64 {
+	for nd := s.root; nd != nil; {
+		switch c := cmp.Compare(k, nd.key); {
+		case c < 0:
+			nd = nd.left
+		case c > 0:
+			nd = nd.right
+		default:
+			return nd.count
+		}
+	}
+	return 0
+}
+
+// Set sets the count for key (<=0 allowed, as in Counter.Set).
+func (s *SortedCounter[T]) Set(k T, n int64) {
+	s.root = s.upsert(s.root, k, func(int64) int64 { return n }, false)
+}
+
+// Add increments key by 1.
+func (s *SortedCounter[T]) Add(k T) {
+	s.root = s.upsert(s.root, k, func(old int64) int64 { return old + 1 }, false)
+}
+
+// AddN increments key by n (n can be negative).
+// If the resulting count is 0, the key is removed, as in Counter.AddN.
+func (s *SortedCounter[T]) AddN(k T, n int64) {
+	if n == 0 {
+		return
+	}
+	s.root = s.upsert(s.root, k, func(old int64) int64 { return old + n }, true)
+}
+
+// Update adds counts from a Counter.
+func (s *SortedCounter[T]) Update(other Counter[T]) {
+	for k, n := range other {
+		s.AddN(k, n)
+	}
+}
+
+// Delete removes a key and returns its previous count (0 if not present).
+func (s *SortedCounter[T]) Delete(k T) (prev int64) {
+	s.root = s.upsert(s.root, k, func(old int64) int64 {
+		prev = old
+		return 0
+	}, true)
+	return prev
+}
+
+// upsert replaces the count of k with op(old), inserting k if missing.
+// With dropZero a resulting count of 0 removes the key.
+func (s *SortedCounter[T]) upsert(nd *sortedNode[T], k T, op func(int64) int64, dropZero bool) *sortedNode[T] {
+	if nd == nil {
+		n := op(0)
+		if n == 0 && dropZero {
+			return nil
+		}
+		s.n++
+		nd = &sortedNode[T]{key: k, count: n, prio: rand.Uint64()}
+		nd.fix()
+		return nd
+	}
+	switch c := cmp.Compare(k, nd.key); {
+	case c < 0:
+		nd.left = s.upsert(nd.left, k, op, dropZero)
+		if nd.left != nil && nd.left.prio > nd.prio {
+			nd = rotateRight(nd)
+		}
+	case c > 0:
+		nd.right = s.upsert(nd.right, k, op, dropZero)
+		if nd.right != nil && nd.right.prio > nd.prio {
+			nd = rotateLeft(nd)
+		}
+	default:
+		nd.count = op(nd.count)
+		if nd.count == 0 && dropZero {
+			s.n--
+			return mergeNodes(nd.left, nd.right)
+		}
+	}
+	nd.fix()
+	return nd
+}
+
+func rotateRight[T cmp.Ordered](nd *sortedNode[T]) *sortedNode[T] {
+	l := nd.left
+	nd.left, l.right = l.right, nd
+	nd.fix()
+	l.fix()
+	return l
+}
+
+func rotateLeft[T cmp.Ordered](nd *sortedNode[T]) *sortedNode[T] {
+	r := nd.right
+	nd.right, r.left = r.left, nd
+	nd.fix()
+	r.fix()
+	return r
+}
+
+// mergeNodes joins two treaps where every key of a is below every key of b.
+func mergeNodes[T cmp.Ordered](a, b *sortedNode[T]) *sortedNode[T] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.prio > b.prio:
+		a.right = mergeNodes(a.right, b)
+		a.fix()
+		return a
+	default:
+		b.left = mergeNodes(a, b.left)
+		b.fix()
+		return b
+	}
+}
+
+// Len returns the number of keys.
+func (s *SortedCounter[T]) Len() int { return s.n }
+
+// Total returns the sum of all positive counts.
+func (s *SortedCounter[T]) Total() int64 { return s.root.total() }
+
+// Rank returns the number of elements with a key strictly below k, i.e. the
+// position k would take in the sorted Elements.
+func (s *SortedCounter[T]) Rank(k T) int64 { return s.below(k, false) }
+
+// below sums the positive counts of keys < k (or <= k if inclusive).
+func (s *SortedCounter[T]) below(k T, inclusive bool) int64 {
+	var acc int64
+	for nd := s.root; nd != nil; {
+		c := cmp.Compare(k, nd.key)
+		if c < 0 || c == 0 && !inclusive {
+			nd = nd.left
+			continue
+		}
+		acc += nd.left.total() + max(nd.count, 0)
+		nd = nd.right
+	}
+	return acc
+}
+
+// RangeCount returns the number of elements with lo <= key <= hi.
+func (s *SortedCounter[T]) RangeCount(lo, hi T) int64 {
+	if cmp.Compare(lo, hi) > 0 {
+		return 0
+	}
+	return s.below(hi, true) - s.below(lo, false)
+}
+
+// Select returns the key of the i-th element (0-based) of the sorted
+// Elements. ok is false if i is outside [0, Total()).
+func (s *SortedCounter[T]) Select(i int64) (k T, ok bool) {
+	if i < 0 {
+		return k, false
+	}
+	for nd := s.root; nd != nil; {
+		l := nd.left.total()
+		if i < l {
+			nd = nd.left
+			continue
+		}
+		i -= l
+		c := max(nd.count, 0)
+		if i < c {
+			return nd.key, true
+		}
+		i -= c
+		nd = nd.right
+	}
+	return k, false
+}
+
+// Quantile returns the smallest key whose cumulative count reaches a q
+// fraction of Total (the nearest-rank method), so Quantile(0.99) is the
+// 99th percentile key and Quantile(0) the smallest one. ok is false if the
+// counter holds no positive counts or q is outside [0, 1].
+func (s *SortedCounter[T]) Quantile(q float64) (k T, ok bool) {
+	total := s.Total()
+	if total == 0 || !(q >= 0 && q <= 1) {
+		return k, false
+	}
+	rank := int64(math.Ceil(q*float64(total))) - 1
+	return s.Select(min(max(rank, 0), total-1))
+}
+
+// All yields every (key, count) pair in ascending key order.
+func (s *SortedCounter[T]) All() iter.Seq2[T, int64] {
+	return func(yield func(T, int64) bool) {
+		s.root.walk(yield)
+	}
+}
+
+func (nd *sortedNode[T]) walk(yield func(T, int64) bool) bool {
+	return nd == nil ||
+		nd.left.walk(yield) && yield(nd.key, nd.count) && nd.right.walk(yield)
+}
+
+// Items returns all (key, count) pairs in ascending key order.
+func (s *SortedCounter[T]) Items() []Pair[T] {
+	out := make([]Pair[T], 0, s.n)
+	for k, n := range s.All() {
+		out = append(out, Pair[T]{k, n})
+	}
+	return out
+}
+
+// Counter returns the entries as a plain Counter.
+func (s *SortedCounter[T]) Counter() Counter[T] {
+	out := make(Counter[T], s.n)
+	for k, n := range s.All() {
+		out[k] = n
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package counter_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestSortedCounterLatencyBuckets(t *testing.T) {
+	// 延迟直方图：键是毫秒桶
+	s := counter.NewSorted[int]()
+	s.AddN(10, 50)
+	s.AddN(20, 30)
+	s.AddN(50, 15)
+	s.AddN(100, 4)
+	s.Add(500)
+
+	assertEqual(t, s.Total(), int64(100))
+	assertEqual(t, s.RangeCount(20, 100), int64(49))
+	assertEqual(t, s.RangeCount(11, 19), int64(0))
+	assertEqual(t, s.RangeCount(100, 10), int64(0))
+	assertEqual(t, s.Rank(50), int64(80))
+	assertEqual(t, s.Rank(51), int64(95))
+
+	for q, want := range map[float64]int{0: 10, 0.5: 10, 0.51: 20, 0.8: 20, 0.95: 50, 0.99: 100, 1: 500} {
+		got, ok := s.Quantile(q)
+		if !ok || got != want {
+			t.Fatalf("Quantile(%v) = %v, %v; want %v", q, got, ok, want)
+		}
+	}
+	if _, ok := s.Quantile(1.5); ok {
+		t.Fatal("Quantile(1.5) should fail")
+	}
+	if _, ok := s.Select(100); ok {
+		t.Fatal("Select(Total) should fail")
+	}
+}
+
+func TestSortedCounterSemantics(t *testing.T) {
+	s := counter.SortedFrom(counter.Counter[string]{"b": 2, "a": 1, "neg": -3})
+	s.AddN("a", -1) // 归零即删除，同 Counter.AddN
+	s.Set("z", 0)   // Set 保留零值，同 Counter.Set
+	s.Add("c")
+	assertEqual(t, s.Get("a"), int64(0))
+	assertEqual(t, s.Len(), 4)
+	assertEqual(t, s.Delete("b"), int64(2))
+	assertSliceEq(t, s.Items(), []counter.Pair[string]{
+		{Key: "c", Count: 1}, {Key: "neg", Count: -3}, {Key: "z", Count: 0},
+	})
+	// 负数与零不占位置
+	assertEqual(t, s.Total(), int64(1))
+	k, _ := s.Select(0)
+	assertEqual(t, k, "c")
+	assertEqual(t, s.Counter().Equal(counter.Counter[string]{"c": 1, "neg": -3}), true)
+}
+
+func TestSortedCounterMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	s := counter.NewSorted[int]()
+	ref := counter.New[int]()
+	for i := 0; i < 5000; i++ {
+		k := rng.IntN(200)
+		switch rng.IntN(4) {
+		case 0:
+			n := rng.Int64N(10) - 3
+			s.Set(k, n)
+			ref.Set(k, n)
+		case 1:
+			s.Delete(k)
+			ref.Delete(k)
+		default:
+			n := rng.Int64N(7) - 2
+			s.AddN(k, n)
+			ref.AddN(k, n)
+		}
+	}
+	assertEqual(t, s.Len(), len(ref))
+	assertEqual(t, s.Total(), ref.Total())
+
+	elems := ref.Elements()
+	slices.Sort(elems)
+	for i, want := range elems {
+		if got, ok := s.Select(int64(i)); !ok || got != want {
+			t.Fatalf("Select(%d) = %v, want %v", i, got, want)
+		}
+	}
+	for k := -1; k <= 201; k++ {
+		rank, _ := slices.BinarySearch(elems, k)
+		assertEqual(t, s.Rank(k), int64(rank))
+		assertEqual(t, s.Get(k), ref.Get(k))
+	}
+	lo, hi := 37, 141
+	var want int64
+	for _, e := range elems {
+		if e >= lo && e <= hi {
+			want++
+		}
+	}
+	assertEqual(t, s.RangeCount(lo, hi), want)
+}
+
+func BenchmarkSortedCounterQuantile(b *testing.B) {
+	s := counter.NewSorted[int]()
+	for i := 0; i < 100000; i++ {
+		s.AddN(i, int64(i%13+1))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Quantile(0.99)
+	}
+}
@@ -0,0 +1,155 @@
+// Package stats computes summary statistics of a counter.Counter viewed as a
+// discrete distribution. Only positive counts take part; zero and negative
+// counts are ignored, as in Counter.Total.
+package stats
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// Probabilities returns each key's share of c.Total(). It returns an empty
+// map if c has no positive counts.
+func Probabilities[T comparable](c counter.Counter[T]) map[T]float64 {
+	out := make(map[T]float64, len(c))
+	total := float64(c.Total())
+	if total == 0 {
+		return out
+	}
+	for k, v := range c {
+		if v > 0 {
+			out[k] = float64(v) / total
+		}
+	}
+	return out
+}
+
+// Entropy returns the Shannon entropy of c in bits.
+func Entropy[T comparable](c counter.Counter[T]) float64 {
+	var h float64
+	for _, p := range Probabilities(c) {
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// Gini returns the Gini coefficient of the counts: 0 when every key has the
+// same count, approaching 1 when a single key holds almost everything.
+func Gini[T comparable](c counter.Counter[T]) float64 {
+	xs := positive(c)
+	if len(xs) == 0 {
+		return 0
+	}
+	slices.Sort(xs)
+	var weighted, sum float64
+	n := float64(len(xs))
+	for i, x := range xs {
+		weighted += (2*float64(i+1) - n - 1) * x
+		sum += x
+	}
+	return weighted / (n * sum)
+}
+
+// KL returns the Kullback-Leibler divergence D(p || q) in bits between the
+// distributions of p and q. It is +Inf if q lacks a key that p has.
+func KL[T comparable](p, q counter.Counter[T]) float64 {
+	pp, qq := Probabilities(p), Probabilities(q)
+	var d float64
+	for k, pk := range pp {
+		qk := qq[k]
+		if qk == 0 {
+			return math.Inf(1)
+		}
+		d += pk * math.Log2(pk/qk)
+	}
+	return d
+}
+
+// JS returns the Jensen-Shannon divergence in bits between the distributions
+// of p and q. It is symmetric and lies in [0, 1].
+func JS[T comparable](p, q counter.Counter[T]) float64 {
+	pp, qq := Probabilities(p), Probabilities(q)
+	m := make(map[T]float64, len(pp)+len(qq))
+	for k, v := range pp {
+		m[k] += v / 2
+	}
+	for k, v := range qq {
+		m[k] += v / 2
+	}
+	half := func(x map[T]float64) float64 {
+		var d float64
+		for k, xk := range x {
+			d += xk * math.Log2(xk/m[k])
+		}
+		return d
+	}
+	return (half(pp) + half(qq)) / 2
+}
+
+// Quantiles returns the q-quantiles of the counts (not the keys) using linear
+// interpolation between order statistics, like numpy.quantile's default.
+// Each q must be in [0, 1]. It returns NaNs if c has no positive counts.
+func Quantiles[T comparable](c counter.Counter[T], qs ...float64) []float64 {
+	xs := positive(c)
+	slices.Sort(xs)
+	out := make([]float64, len(qs))
+	for i, q := range qs {
+		if len(xs) == 0 || !(q >= 0 && q <= 1) { // also catches NaN
+			out[i] = math.NaN()
+			continue
+		}
+		pos := q * float64(len(xs)-1)
+		lo := int(pos)
+		if lo == len(xs)-1 {
+			out[i] = xs[lo]
+			continue
+		}
+		out[i] = xs[lo] + (pos-float64(lo))*(xs[lo+1]-xs[lo])
+	}
+	return out
+}
+
+// Histogram renders the top-n entries of c (n <= 0 means all) as a text bar
+// chart whose longest bar is width characters, e.g.
+//
+//	go   ████████████ 3
+//	py   ████████     2
+func Histogram[T comparable](c counter.Counter[T], n, width int) string {
+	pairs := c.MostCommon(n)
+	if len(pairs) == 0 || width <= 0 {
+		return ""
+	}
+	labels := make([]string, len(pairs))
+	labelW := 0
+	for i, p := range pairs {
+		labels[i] = fmt.Sprint(p.Key)
+		labelW = max(labelW, len([]rune(labels[i])))
+	}
+	peak := max(pairs[0].Count, 1)
+
+	var b strings.Builder
+	for i, p := range pairs {
+		bar := 0
+		if p.Count > 0 {
+			bar = int(math.Round(float64(p.Count) / float64(peak) * float64(width)))
+		}
+		pad := labelW - len([]rune(labels[i]))
+		fmt.Fprintf(&b, "%s%s %s%s %d\n", labels[i], strings.Repeat(" ", pad),
+			strings.Repeat("█", bar), strings.Repeat(" ", width-bar), p.Count)
+	}
+	return b.String()
+}
+
+func positive[T comparable](c counter.Counter[T]) []float64 {
+	xs := make([]float64, 0, len(c))
+	for _, v := range c {
+		if v > 0 {
+			xs = append(xs, float64(v))
+		}
+	}
+	return xs
+}
@@ -0,0 +1,70 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/stats"
+)
+
+func near(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 && !(math.IsInf(got, 1) && math.IsInf(want, 1)) {
+		t.Fatalf("%s: got %v want %v", name, got, want)
+	}
+}
+
+// 参考值来自 scipy.stats.entropy(base=2) / numpy.quantile / scipy.spatial.distance.jensenshannon**2
+func TestReferenceValues(t *testing.T) {
+	type C = counter.Counter[string]
+	uniform := C{"a": 1, "b": 1}
+	skew := C{"a": 1, "b": 1, "c": 2, "x": 0, "y": -3}
+
+	near(t, "entropy uniform", stats.Entropy(uniform), 1)
+	near(t, "entropy skew", stats.Entropy(skew), 1.5)
+	near(t, "entropy empty", stats.Entropy(C{}), 0)
+
+	probs := stats.Probabilities(skew)
+	near(t, "p(c)", probs["c"], 0.5)
+	if _, ok := probs["y"]; ok {
+		t.Fatal("negative count must be ignored")
+	}
+
+	near(t, "gini equal", stats.Gini(C{"a": 3, "b": 3, "c": 3}), 0)
+	near(t, "gini 1..4", stats.Gini(C{"a": 1, "b": 2, "c": 3, "d": 4}), 0.25)
+
+	p, q := C{"a": 1, "b": 1}, C{"a": 1, "b": 3}
+	near(t, "kl", stats.KL(p, q), 0.20751874963942196)
+	near(t, "kl missing", stats.KL(C{"z": 1}, q), math.Inf(1))
+	near(t, "kl self", stats.KL(p, p), 0)
+	near(t, "js disjoint", stats.JS(C{"a": 1}, C{"b": 1}), 1)
+	near(t, "js symmetric", stats.JS(p, q), stats.JS(q, p))
+	near(t, "js", stats.JS(p, q), 0.04879494069539847)
+
+	qs := stats.Quantiles(C{"a": 1, "b": 2, "c": 3, "d": 4}, 0, 0.25, 0.5, 0.99, 1)
+	for i, want := range []float64{1, 1.75, 2.5, 3.97, 4} {
+		near(t, "quantile", qs[i], want)
+	}
+	if !math.IsNaN(stats.Quantiles(C{}, 0.5)[0]) {
+		t.Fatal("quantile of empty counter should be NaN")
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if got := stats.Quantiles(C{"a": 1, "b": 2}, q)[0]; !math.IsNaN(got) {
+			t.Fatalf("Quantiles(q=%v) = %v, want NaN", q, got)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	c := counter.Counter[string]{"go": 4, "py": 2, "c": 1}
+	want := "" +
+		"go ████████ 4\n" +
+		"py ████     2\n"
+	if got := stats.Histogram(c, 2, 8); got != want {
+		t.Fatalf("\n got:\n%s\nwant:\n%s", got, want)
+	}
+	if stats.Histogram(counter.Counter[int]{}, 0, 10) != "" {
+		t.Fatal("empty counter should render nothing")
+	}
+}
@@ -0,0 +1,250 @@
+package counter
+
+import "sync/atomic"
+
+// Direction tells which way a count crossed a threshold.
+type Direction int
+
+const (
+	// Up means the count rose from below the threshold to at least it.
+	Up Direction = iota + 1
+	// Down means the count fell from at least the threshold to below it.
+	Down
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	}
+	return "unknown"
+}
+
+// Crossing is delivered to watchers when a key's count crosses a threshold.
+type Crossing[T comparable] struct {
+	Key       T
+	Old, New  int64
+	Threshold int64
+	Direction Direction
+}
+
+// crossing reports how old -> new crosses threshold, or 0 if it does not.
+func crossing(old, new, threshold int64) Direction {
+	switch {
+	case old < threshold && new >= threshold:
+		return Up
+	case old >= threshold && new < threshold:
+		return Down
+	}
+	return 0
+}
+
+type watcher[T comparable] struct {
+	threshold int64
+	fn        func(Crossing[T])
+	stopped   atomic.Bool
+}
+
+type watchEvent[T comparable] struct {
+	w *watcher[T]
+	c Crossing[T]
+}
+
+// watchState holds a SafeCounter's watchers; every field is guarded by
+// SafeCounter.mu.
+type watchState[T comparable] struct {
+	byKey    map[T][]*watcher[T]
+	all      []*watcher[T]
+	pending  []watchEvent[T]
+	draining bool
+}
+
+// Watch calls fn whenever the count of key crosses threshold, upward or
+// downward (see Direction). It returns a function that removes the watcher.
+//
+// Delivery guarantees:
+//   - fn never runs while the counter's lock is held, so it may call any
+//     SafeCounter method, including mutating ones.
+//   - Callbacks run one at a time, in the order the mutations were applied.
+//     Crossings caused by one bulk call (Update, Subtract, ...) have no
+//     defined order among themselves.
+//   - The callback may run on another goroutine that is already delivering,
+//     so a mutating call can return before its callbacks have run.
+func (s *SafeCounter[T]) Watch(key T, threshold int64, fn func(Crossing[T])) (stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ws := s.watchState()
+	w := &watcher[T]{threshold: threshold, fn: fn}
+	ws.byKey[key] = append(ws.byKey[key], w)
+	return func() {
+		w.stopped.Store(true)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ws.byKey[key] = removeWatcher(ws.byKey[key], w)
+		if len(ws.byKey[key]) == 0 {
+			delete(ws.byKey, key)
+		}
+	}
+}
+
+// WatchAll is Watch for every key of the counter.
+func (s *SafeCounter[T]) WatchAll(threshold int64, fn func(Crossing[T])) (stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ws := s.watchState()
+	w := &watcher[T]{threshold: threshold, fn: fn}
+	ws.all = append(ws.all, w)
+	return func() {
+		w.stopped.Store(true)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ws.all = removeWatcher(ws.all, w)
+	}
+}
+
+// SendTo adapts a channel for use as a Watch callback. Sends block, so
+// delivery of later crossings waits until the receiver keeps up.
+func SendTo[T comparable](ch chan<- Crossing[T]) func(Crossing[T]) {
+	return func(c Crossing[T]) { ch <- c }
+}
+
+func removeWatcher[T comparable](ws []*watcher[T], w *watcher[T]) []*watcher[T] {
+	for i, x := range ws {
+		if x == w {
+			return append(ws[:i:i], ws[i+1:]...)
+		}
+	}
+	return ws
+}
+
+// watchState returns s.watch, allocating it on first use. Caller holds mu.
+func (s *SafeCounter[T]) watchState() *watchState[T] {
+	if s.watch == nil {
+		s.watch = &watchState[T]{byKey: make(map[T][]*watcher[T])}
+	}
+	return s.watch
+}
+
+// record queues crossings for a change of k from old to new. Caller holds mu.
+func (s *SafeCounter[T]) record(k T, old, new int64) {
+	if old == new {
+		return
+	}
+	ws := s.watch
+	for _, w := range ws.byKey[k] {
+		if d := crossing(old, new, w.threshold); d != 0 {
+			ws.pending = append(ws.pending, watchEvent[T]{w, Crossing[T]{k, old, new, w.threshold, d}})
+		}
+	}
+	for _, w := range ws.all {
+		if d := crossing(old, new, w.threshold); d != 0 {
+			ws.pending = append(ws.pending, watchEvent[T]{w, Crossing[T]{k, old, new, w.threshold, d}})
+		}
+	}
+}
+
+// claimDrain reports whether the caller should deliver pending events after
+// releasing mu. Only one goroutine drains at a time. Caller holds mu.
+func (s *SafeCounter[T]) claimDrain() bool {
+	ws := s.watch
+	if ws == nil || ws.draining || len(ws.pending) == 0 {
+		return false
+	}
+	ws.draining = true
+	return true
+}
+
+// drain delivers pending events until none are left. It must be called
+// without holding mu, and only after claimDrain returned true.
+//
+// If a callback panics, the panic propagates to the caller, but the drain
+// claim is released first and the undelivered events are put back in the
+// queue, so the next mutation resumes delivery.
+func (s *SafeCounter[T]) drain() {
+	var (
+		batch []watchEvent[T]
+		next  int // index in batch of the next event to deliver
+		done  bool
+	)
+	defer func() {
+		if done {
+			return
+		}
+		s.mu.Lock()
+		ws := s.watch
+		ws.pending = append(batch[next:len(batch):len(batch)], ws.pending...)
+		ws.draining = false
+		s.mu.Unlock()
+	}()
+	for {
+		s.mu.Lock()
+		ws := s.watch
+		batch, next = ws.pending, 0
+		ws.pending = nil
+		if len(batch) == 0 {
+			ws.draining = false
+			s.mu.Unlock()
+			done = true
+			return
+		}
+		s.mu.Unlock()
+		for next < len(batch) {
+			e := batch[next]
+			next++
+			if !e.w.stopped.Load() {
+				e.w.fn(e.c)
+			}
+		}
+	}
+}
+
+// writeKey applies op, which may only change key k, under the write lock
+// and then notifies watchers.
+func (s *SafeCounter[T]) writeKey(k T, op func()) {
+	s.mu.Lock()
+	if s.watch == nil {
+		op()
+		s.mu.Unlock()
+		return
+	}
+	old := s.c[k]
+	op()
+	s.record(k, old, s.c[k])
+	claimed := s.claimDrain()
+	s.mu.Unlock()
+	if claimed {
+		s.drain()
+	}
+}
+
+// writeKeys is writeKey for ops that may change any key yielded by keys.
+func (s *SafeCounter[T]) writeKeys(keys func(yield func(T)), op func()) {
+	s.mu.Lock()
+	if s.watch == nil {
+		op()
+		s.mu.Unlock()
+		return
+	}
+	olds := make(map[T]int64)
+	keys(func(k T) { olds[k] = s.c[k] })
+	op()
+	for k, old := range olds {
+		s.record(k, old, s.c[k])
+	}
+	claimed := s.claimDrain()
+	s.mu.Unlock()
+	if claimed {
+		s.drain()
+	}
+}
+
+// keysOf yields the keys of c, for writeKeys.
+func keysOf[T comparable](c Counter[T]) func(yield func(T)) {
+	return func(yield func(T)) {
+		for k := range c {
+			yield(k)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package counter_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestWatchKeyCrossings(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	var got []counter.Crossing[string]
+	stop := sc.Watch("login_fail", 3, func(c counter.Crossing[string]) { got = append(got, c) })
+
+	sc.Add("login_fail")
+	sc.Add("other") // 其他键不触发
+	sc.AddN("login_fail", 2)
+	sc.Add("login_fail") // 已在阈值之上，不再触发
+	sc.Set("login_fail", 1)
+	sc.Update(counter.Counter[string]{"login_fail": 5})
+	sc.Delete("login_fail")
+
+	want := []counter.Crossing[string]{
+		{Key: "login_fail", Old: 1, New: 3, Threshold: 3, Direction: counter.Up},
+		{Key: "login_fail", Old: 4, New: 1, Threshold: 3, Direction: counter.Down},
+		{Key: "login_fail", Old: 1, New: 6, Threshold: 3, Direction: counter.Up},
+		{Key: "login_fail", Old: 6, New: 0, Threshold: 3, Direction: counter.Down},
+	}
+	assertSliceEq(t, got, want)
+
+	stop()
+	sc.AddN("login_fail", 10)
+	assertEqual(t, len(got), len(want))
+}
+
+func TestWatchAllAndChannel(t *testing.T) {
+	sc := counter.NewSafe[int]()
+	ch := make(chan counter.Crossing[int], 10)
+	stop := sc.WatchAll(2, counter.SendTo(ch))
+	defer stop()
+
+	sc.UpdateSlice([]int{1, 1, 2})
+	sc.Subtract(counter.Counter[int]{1: 1})
+	if err := json.Unmarshal([]byte(`[[7,9]]`), sc); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, <-ch, counter.Crossing[int]{Key: 1, Old: 0, New: 2, Threshold: 2, Direction: counter.Up})
+	assertEqual(t, <-ch, counter.Crossing[int]{Key: 1, Old: 2, New: 1, Threshold: 2, Direction: counter.Down})
+	assertEqual(t, (<-ch).Key, 7)
+	assertEqual(t, len(ch), 0)
+}
+
+func TestWatchCallbackMayMutate(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	var order []string
+	sc.Watch("a", 1, func(c counter.Crossing[string]) {
+		order = append(order, "a")
+		sc.Add("b") // 回调里写同一个计数器：不能死锁
+	})
+	sc.Watch("b", 1, func(c counter.Crossing[string]) {
+		order = append(order, "b")
+		assertEqual(t, sc.Get("b"), int64(1))
+	})
+	sc.Add("a")
+	assertSliceEq(t, order, []string{"a", "b"})
+}
+
+func TestWatchSerialDeliveryUnderContention(t *testing.T) {
+	sc := counter.NewSafe[int]()
+	var (
+		mu      sync.Mutex
+		active  int
+		crossed int
+	)
+	sc.WatchAll(1, func(c counter.Crossing[int]) {
+		mu.Lock()
+		active++
+		if active > 1 {
+			t.Error("callbacks ran concurrently")
+		}
+		crossed++
+		mu.Unlock()
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				sc.Add(g*1000 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	sc.Add(-1) // 确保最后一批已投递：此时没有其他写者在投递
+	mu.Lock()
+	defer mu.Unlock()
+	assertEqual(t, crossed, 801)
+}
+
+func TestWatchCallbackPanicDoesNotStopDelivery(t *testing.T) {
+	sc := counter.NewSafe[string]()
+	var got []int64
+	sc.Watch("k", 1, func(c counter.Crossing[string]) {
+		if c.New == 1 {
+			panic("boom")
+		}
+	})
+	sc.WatchAll(1, func(c counter.Crossing[string]) { got = append(got, c.New) })
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("want the callback panic to propagate, got %v", r)
+			}
+		}()
+		sc.Add("k")
+	}()
+	// 回调 panic 之后，未投递的事件和后续越界仍然要送达
+	sc.Set("k", 0)
+	sc.Set("k", 2)
+	assertSliceEq(t, got, []int64{1, 0, 2})
+}
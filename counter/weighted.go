@@ -0,0 +1,196 @@
+package counter
+
+import (
+	"math"
+	"slices"
+)
+
+// Number is the set of count types a Weighted counter accepts.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Weighted is Counter with a caller-chosen count type, for weighted events
+// such as bytes (int64), durations or probabilities (float64).
+// Zero value is ready to read; use NewWeighted (or make) before writing.
+//
+// With float counts, a key whose count cancels out to (almost) zero is
+// removed: AddN, Update and Subtract treat a result within a relative
+// rounding error of the operands as zero, so 0.1+0.2-0.3 drops the key.
+type Weighted[T comparable, N Number] map[T]N
+
+// WeightedPair holds a (Key, Count) entry of a Weighted counter.
+type WeightedPair[T comparable, N Number] struct {
+	Key   T
+	Count N
+}
+
+// NewWeighted creates an empty Weighted counter.
+func NewWeighted[T comparable, N Number]() Weighted[T, N] { return make(Weighted[T, N]) }
+
+// WeightedFrom converts a Counter into a Weighted counter with count type N.
+func WeightedFrom[N Number, T comparable](c Counter[T]) Weighted[T, N] {
+	out := make(Weighted[T, N], len(c))
+	for k, v := range c {
+		out[k] = N(v)
+	}
+	return out
+}
+
+// isFloat reports whether N is a floating-point type.
+func isFloat[N Number]() bool {
+	var half N = 1
+	half /= 2
+	return half != 0
+}
+
+// cancelled reports whether sum = a + b is zero, or for floats, small enough
+// relative to a and b to be rounding noise.
+func cancelled[N Number](sum, a, b N) bool {
+	if sum == 0 {
+		return true
+	}
+	if !isFloat[N]() {
+		return false
+	}
+	const eps = 1e-12
+	scale := max(math.Abs(float64(a)), math.Abs(float64(b)))
+	return math.Abs(float64(sum)) <= eps*scale
+}
+
+// Clone returns a deep copy.
+func (w Weighted[T, N]) Clone() Weighted[T, N] {
+	out := make(Weighted[T, N], len(w))
+	for k, v := range w {
+		out[k] = v
+	}
+	return out
+}
+
+// Get returns count for key (0 if missing).
+func (w Weighted[T, N]) Get(k T) N { return w[k] }
+
+// Set sets the count for key.
+func (w Weighted[T, N]) Set(k T, n N) { w[k] = n }
+
+// Add increments key by 1.
+func (w Weighted[T, N]) Add(k T) { w.AddN(k, 1) }
+
+// AddN adds n to key; a result that cancels to zero removes the key.
+func (w Weighted[T, N]) AddN(k T, n N) {
+	if n == 0 {
+		return
+	}
+	old := w[k]
+	if sum := old + n; cancelled(sum, old, n) {
+		delete(w, k)
+	} else {
+		w[k] = sum
+	}
+}
+
+// Update adds counts from another Weighted counter.
+func (w Weighted[T, N]) Update(other Weighted[T, N]) {
+	for k, v := range other {
+		w.AddN(k, v)
+	}
+}
+
+// Subtract subtracts counts from another Weighted counter. For unsigned N
+// counts wrap around just like the underlying integers.
+func (w Weighted[T, N]) Subtract(other Weighted[T, N]) {
+	for k, v := range other {
+		if v == 0 {
+			continue
+		}
+		old := w[k]
+		if diff := old - v; cancelled(diff, old, v) {
+			delete(w, k)
+		} else {
+			w[k] = diff
+		}
+	}
+}
+
+// Delete removes a key and returns its previous count.
+func (w Weighted[T, N]) Delete(k T) (prev N) {
+	prev = w[k]
+	delete(w, k)
+	return
+}
+
+// Total returns the sum of all positive counts.
+func (w Weighted[T, N]) Total() N {
+	var s N
+	for _, v := range w {
+		if v > 0 {
+			s += v
+		}
+	}
+	return s
+}
+
+// TotalRaw returns the algebraic sum of all counts.
+func (w Weighted[T, N]) TotalRaw() N {
+	var s N
+	for _, v := range w {
+		s += v
+	}
+	return s
+}
+
+// Keys returns all keys (order undefined).
+func (w Weighted[T, N]) Keys() []T {
+	out := make([]T, 0, len(w))
+	for k := range w {
+		out = append(out, k)
+	}
+	return out
+}
+
+// Items returns all (key, count) pairs (unsorted).
+func (w Weighted[T, N]) Items() []WeightedPair[T, N] {
+	out := make([]WeightedPair[T, N], 0, len(w))
+	for k, v := range w {
+		out = append(out, WeightedPair[T, N]{k, v})
+	}
+	return out
+}
+
+// MostCommon returns top-n pairs sorted by Count desc, then by key like
+// Counter.MostCommon. If n <= 0 or n >= len(w), returns all sorted.
+// NaN counts sort last.
+func (w Weighted[T, N]) MostCommon(n int) []WeightedPair[T, N] {
+	items := w.Items()
+	keyCmp := defaultCompare[T]()
+	slices.SortFunc(items, func(a, b WeightedPair[T, N]) int {
+		if r := cmpDesc(a.Count, b.Count); r != 0 {
+			return r
+		}
+		return keyCmp(a.Key, b.Key)
+	})
+	if n <= 0 || n >= len(items) {
+		return items
+	}
+	return items[:n]
+}
+
+// cmpDesc orders a before b when a is larger; NaNs go last.
+func cmpDesc[N Number](a, b N) int {
+	aNaN, bNaN := a != a, b != b
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return 1
+	case bNaN:
+		return -1
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
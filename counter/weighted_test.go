@@ -0,0 +1,62 @@
+package counter_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+func TestWeightedFloat(t *testing.T) {
+	w := counter.NewWeighted[string, float64]()
+	w.AddN("p", 0.1)
+	w.AddN("p", 0.2)
+	w.AddN("p", -0.3) // 0.1+0.2-0.3 = 5.5e-17：浮点误差，应视为 0 并删除
+	assertEqual(t, len(w), 0)
+
+	w.Update(counter.Weighted[string, float64]{"a": 1.5, "b": 0.25, "c": -1})
+	w.Subtract(counter.Weighted[string, float64]{"b": 0.25, "a": 0.5})
+	assertEqual(t, w.Get("a"), 1.0)
+	_, hasB := w["b"]
+	assertEqual(t, hasB, false)
+	assertEqual(t, w.Total(), 1.0)
+	assertEqual(t, w.TotalRaw(), 0.0)
+
+	// 小而非零的结果保留
+	w.AddN("tiny", 1e-20)
+	assertEqual(t, w.Get("tiny"), 1e-20)
+
+	w.Set("nan", math.NaN())
+	top := w.MostCommon(0)
+	assertEqual(t, top[0].Key, "a")
+	assertEqual(t, top[len(top)-1].Key, "nan")
+	assertEqual(t, len(w.MostCommon(2)), 2)
+}
+
+func TestWeightedDurations(t *testing.T) {
+	w := counter.NewWeighted[string, time.Duration]()
+	w.AddN("GET /", 120*time.Millisecond)
+	w.AddN("POST /login", 2*time.Second)
+	w.AddN("GET /", 80*time.Millisecond)
+	w.Add("GET /health")
+
+	top := w.MostCommon(2)
+	assertEqual(t, top[0], counter.WeightedPair[string, time.Duration]{Key: "POST /login", Count: 2 * time.Second})
+	assertEqual(t, top[1].Count, 200*time.Millisecond)
+	assertEqual(t, w.Total(), 2200*time.Millisecond+1)
+
+	w.AddN("GET /health", -1)
+	assertEqual(t, len(w.Keys()), 2)
+	assertEqual(t, w.Delete("GET /"), 200*time.Millisecond)
+}
+
+func TestWeightedFromCounter(t *testing.T) {
+	w := counter.WeightedFrom[float64](counter.Counter[int]{1: 3, 2: 1})
+	w.AddN(2, 0.5)
+	assertEqual(t, w.Get(2), 1.5)
+	clone := w.Clone()
+	clone.Add(1)
+	assertEqual(t, w.Get(1), 3.0)
+	assertEqual(t, len(w.Items()), 2)
+}
@@ -0,0 +1,116 @@
+package counter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock abstracts the time source so tests can advance time deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+//// Sliding-window variant /////////////////////////////////////////////////////
+
+// Windowed counts keys over a sliding time window. The window is split into
+// equal buckets, each a Counter; as time passes the oldest bucket is dropped,
+// so counts expire with a granularity of window/buckets.
+// Windowed is safe for concurrent use.
+type Windowed[T comparable] struct {
+	mu     sync.Mutex
+	clock  Clock
+	width  time.Duration // duration of one bucket
+	slots  []Counter[T]
+	head   int       // index of the current bucket
+	headAt time.Time // start of the current bucket
+}
+
+// NewWindowed creates a counter over the last window of time, split into
+// buckets slots. A nil clock means the system clock. It panics if window or
+// buckets is not positive, or window is shorter than buckets nanoseconds.
+func NewWindowed[T comparable](window time.Duration, buckets int, clock Clock) *Windowed[T] {
+	if window <= 0 || buckets <= 0 || window/time.Duration(buckets) == 0 {
+		panic(fmt.Sprintf("counter: invalid window %v with %d buckets", window, buckets))
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+	w := &Windowed[T]{
+		clock: clock,
+		width: window / time.Duration(buckets),
+		slots: make([]Counter[T], buckets),
+	}
+	for i := range w.slots {
+		w.slots[i] = New[T]()
+	}
+	w.headAt = clock.Now().Truncate(w.width)
+	return w
+}
+
+// advance rotates out buckets that fell out of the window. Caller holds mu.
+func (w *Windowed[T]) advance() {
+	steps := int64(w.clock.Now().Sub(w.headAt) / w.width)
+	if steps <= 0 {
+		return
+	}
+	if steps >= int64(len(w.slots)) {
+		for _, s := range w.slots {
+			clear(s)
+		}
+	} else {
+		for i := int64(0); i < steps; i++ {
+			w.head = (w.head + 1) % len(w.slots)
+			clear(w.slots[w.head])
+		}
+	}
+	w.headAt = w.headAt.Add(time.Duration(steps) * w.width)
+}
+
+// Window returns the total span covered by the counter.
+func (w *Windowed[T]) Window() time.Duration { return w.width * time.Duration(len(w.slots)) }
+
+// Add increments key by 1 in the current bucket.
+func (w *Windowed[T]) Add(k T) { w.AddN(k, 1) }
+
+// AddN increments key by n in the current bucket.
+func (w *Windowed[T]) AddN(k T, n int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance()
+	w.slots[w.head].AddN(k, n)
+}
+
+// Get returns the count of key within the window.
+func (w *Windowed[T]) Get(k T) int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance()
+	var n int64
+	for _, s := range w.slots {
+		n += s[k]
+	}
+	return n
+}
+
+// Total returns the sum of positive counts within the window.
+func (w *Windowed[T]) Total() int64 { return w.Snapshot().Total() }
+
+// MostCommon returns the top-n keys within the window, see Counter.MostCommon.
+func (w *Windowed[T]) MostCommon(n int) []Pair[T] { return w.Snapshot().MostCommon(n) }
+
+// Snapshot merges the live buckets into a new Counter.
+func (w *Windowed[T]) Snapshot() Counter[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance()
+	out := New[T]()
+	for _, s := range w.slots {
+		out.Update(s)
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package counter_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// fakeClock is a manually advanced counter.Clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestWindowedExpiry(t *testing.T) {
+	clk := newFakeClock()
+	w := counter.NewWindowed[string](5*time.Minute, 5, clk)
+	assertEqual(t, w.Window(), 5*time.Minute)
+
+	w.Add("login")
+	w.AddN("login", 2)
+	clk.Advance(2 * time.Minute)
+	w.Add("login")
+	w.Add("logout")
+	assertEqual(t, w.Get("login"), int64(4))
+	assertEqual(t, w.Total(), int64(5))
+
+	// 前 3 次在 t=0 的桶里，t=5m 时过期
+	clk.Advance(3 * time.Minute)
+	assertEqual(t, w.Get("login"), int64(1))
+	assertEqual(t, w.MostCommon(1)[0], counter.Pair[string]{Key: "login", Count: 1})
+
+	clk.Advance(2 * time.Minute)
+	assertEqual(t, w.Get("login"), int64(0))
+	assertEqual(t, w.Total(), int64(0))
+}
+
+func TestWindowedLongGap(t *testing.T) {
+	clk := newFakeClock()
+	w := counter.NewWindowed[int](time.Second, 10, clk)
+	for i := 0; i < 10; i++ {
+		w.Add(i)
+		clk.Advance(100 * time.Millisecond)
+	}
+	assertEqual(t, w.Total(), int64(9))
+	clk.Advance(time.Hour)
+	assertEqual(t, len(w.Snapshot()), 0)
+	w.Add(42)
+	assertEqual(t, w.Get(42), int64(1))
+}
+
+func TestWindowedConcurrent(t *testing.T) {
+	clk := newFakeClock()
+	w := counter.NewWindowed[int](time.Minute, 6, clk)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				w.Add(i % 3)
+				_ = w.Get(0)
+			}
+		}()
+	}
+	wg.Wait()
+	assertEqual(t, w.Total(), int64(4000))
+}
@@ -0,0 +1,170 @@
+// Package wordfreq streams text from an io.Reader into a counter.Counter[string].
+package wordfreq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fightingBald/py-ds/counter"
+)
+
+// Tokenizer splits the input into tokens; it has the bufio.SplitFunc contract,
+// so bufio.ScanWords, bufio.ScanLines and friends can be used directly.
+type Tokenizer = bufio.SplitFunc
+
+// Whitespace splits on Unicode white space and keeps punctuation attached,
+// like Python's str.split().
+var Whitespace Tokenizer = bufio.ScanWords
+
+// UnicodeWords returns runs of letters, digits and marks, with inner
+// apostrophes kept ("don't"); everything else separates tokens.
+func UnicodeWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	// partial reports whether a rune starting at i may be cut off by the
+	// buffer end, in which case the scanner must supply more data.
+	partial := func(i int) bool { return !atEOF && !utf8.FullRune(data[i:]) }
+
+	start := 0
+	for start < len(data) {
+		if partial(start) {
+			return start, nil, nil
+		}
+		r, w := utf8.DecodeRune(data[start:])
+		if isWordRune(r) {
+			break
+		}
+		start += w
+	}
+	for i := start; i < len(data); {
+		if partial(i) {
+			return start, nil, nil
+		}
+		r, w := utf8.DecodeRune(data[i:])
+		if !isWordRune(r) {
+			if r == '\'' || r == '’' {
+				// Keep the apostrophe only if a word rune follows it.
+				j := i + w
+				if j == len(data) || partial(j) {
+					if !atEOF {
+						return start, nil, nil
+					}
+				} else if next, _ := utf8.DecodeRune(data[j:]); isWordRune(next) {
+					i = j
+					continue
+				}
+			}
+			return i + w, data[start:i], nil
+		}
+		i += w
+	}
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Builder counts tokens read from text. The zero value splits on white space
+// and counts single tokens verbatim.
+type Builder struct {
+	// Tokenizer splits the input; nil means Whitespace.
+	Tokenizer Tokenizer
+	// FoldCase lower-cases tokens before stop-word filtering and counting.
+	FoldCase bool
+	// StopWords are dropped before n-grams are formed. When FoldCase is set,
+	// they match regardless of case.
+	StopWords map[string]struct{}
+	// N counts n-grams of N consecutive tokens joined by a single space;
+	// values below 2 count single tokens.
+	N int
+}
+
+// MaxTokenSize is the longest token the Builder accepts.
+const MaxTokenSize = 1 << 20
+
+// Count reads r to EOF and returns the token counts.
+func (b *Builder) Count(r io.Reader) (counter.Counter[string], error) {
+	c := counter.New[string]()
+	err := b.CountInto(c, r)
+	return c, err
+}
+
+// CountInto reads r to EOF and adds the token counts to c. On a read error,
+// c keeps the counts gathered so far.
+func (b *Builder) CountInto(c counter.Counter[string], r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), MaxTokenSize)
+	split := b.Tokenizer
+	if split == nil {
+		split = Whitespace
+	}
+	sc.Split(split)
+	stopWords := b.StopWords
+	if b.FoldCase {
+		stopWords = foldSet(stopWords)
+	}
+
+	var window []string // last N tokens for n-grams
+	for sc.Scan() {
+		tok := sc.Text()
+		if b.FoldCase {
+			tok = strings.ToLower(tok)
+		}
+		if _, stop := stopWords[tok]; stop {
+			continue
+		}
+		if b.N < 2 {
+			c.Add(tok)
+			continue
+		}
+		window = append(window, tok)
+		if len(window) > b.N {
+			window = window[1:]
+		}
+		if len(window) == b.N {
+			c.Add(strings.Join(window, " "))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("wordfreq: %w", err)
+	}
+	return nil
+}
+
+// foldSet returns set with every word lower-cased.
+func foldSet(set map[string]struct{}) map[string]struct{} {
+	folded := make(map[string]struct{}, len(set))
+	for w := range set {
+		folded[strings.ToLower(w)] = struct{}{}
+	}
+	return folded
+}
+
+// StopWordSet builds a StopWords set from a list of words.
+func StopWordSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// ReadStopWords reads white-space separated stop words from r.
+func ReadStopWords(r io.Reader) (map[string]struct{}, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	set := make(map[string]struct{})
+	for sc.Scan() {
+		set[sc.Text()] = struct{}{}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("wordfreq: stop words: %w", err)
+	}
+	return set, nil
+}
@@ -0,0 +1,112 @@
+package wordfreq_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/fightingBald/py-ds/counter"
+	"github.com/fightingBald/py-ds/counter/wordfreq"
+)
+
+func count(t *testing.T, b wordfreq.Builder, text string) counter.Counter[string] {
+	t.Helper()
+	// OneByteReader 逐字节喂数据，检验分词器在缓冲区边界上的行为
+	c, err := b.Count(iotest.OneByteReader(strings.NewReader(text)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestBuilder(t *testing.T) {
+	const text = "The cat, the hat. Don't stop — café CAFÉ 42!"
+	cases := []struct {
+		name string
+		b    wordfreq.Builder
+		want counter.Counter[string]
+	}{
+		{
+			name: "whitespace default",
+			b:    wordfreq.Builder{},
+			want: counter.Counter[string]{"The": 1, "cat,": 1, "the": 1, "hat.": 1, "Don't": 1,
+				"stop": 1, "—": 1, "café": 1, "CAFÉ": 1, "42!": 1},
+		},
+		{
+			name: "unicode words folded",
+			b:    wordfreq.Builder{Tokenizer: wordfreq.UnicodeWords, FoldCase: true},
+			want: counter.Counter[string]{"the": 2, "cat": 1, "hat": 1, "don't": 1, "stop": 1,
+				"café": 2, "42": 1},
+		},
+		{
+			name: "stop words and bigrams",
+			b: wordfreq.Builder{
+				Tokenizer: wordfreq.UnicodeWords,
+				FoldCase:  true,
+				StopWords: wordfreq.StopWordSet("the", "don't"),
+				N:         2,
+			},
+			want: counter.Counter[string]{"cat hat": 1, "hat stop": 1, "stop café": 1,
+				"café café": 1, "café 42": 1},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := count(t, tc.b, text)
+			if !got.Equal(tc.want) {
+				t.Fatalf("\n got: %v\nwant: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnicodeWordsTrailingApostrophe(t *testing.T) {
+	sc := bufio.NewScanner(strings.NewReader("rock 'n' roll dogs'"))
+	sc.Split(wordfreq.UnicodeWords)
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	if strings.Join(got, "|") != "rock|n|roll|dogs" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestCountIntoAndStopWordFile(t *testing.T) {
+	stop, err := wordfreq.ReadStopWords(strings.NewReader("a\nan  the\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := counter.Counter[string]{"go": 1}
+	b := wordfreq.Builder{StopWords: stop}
+	if err := b.CountInto(c, strings.NewReader("a go an go the")); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equal(counter.Counter[string]{"go": 3}) {
+		t.Fatalf("got %v", c)
+	}
+}
+
+func TestFoldCaseStopWords(t *testing.T) {
+	// 停用词表本身不必是小写
+	b := wordfreq.Builder{FoldCase: true, StopWords: wordfreq.StopWordSet("The", "AND")}
+	c, err := b.Count(strings.NewReader("The cat and THE dog"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equal(counter.Counter[string]{"cat": 1, "dog": 1}) {
+		t.Fatalf("got %v", c)
+	}
+	if _, ok := b.StopWords["the"]; ok {
+		t.Fatal("CountInto must not modify StopWords")
+	}
+}
+
+func TestCountReadError(t *testing.T) {
+	var b wordfreq.Builder
+	_, err := b.Count(iotest.ErrReader(iotest.ErrTimeout))
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("want wrapped timeout, got %v", err)
+	}
+}
@@ -1,8 +1,7 @@
 package counter
 
 import (
-	"fmt"
-	"slices"
+	generic "github.com/fightingBald/py-ds/counter"
 )
 
 // Problem: Build a Counter type mirroring Python's collections.Counter for strings.
@@ -27,41 +26,75 @@ type Item struct {
 }
 
 // The zero value of Counter must be ready to use, just like Python's Counter().
+//
+// Counter is a typed facade over the generic counter.Counter[string]; the
+// map is allocated on the first write.
 
 type Counter struct {
-	counts map[string]int
+	counts generic.Counter[string]
 }
 
 func NewCounter() *Counter {
-	return &Counter{counts: make(map[string]int)}
+	return &Counter{counts: generic.New[string]()}
+}
+
+// FromSlice creates a counter from initial data.
+func FromSlice(elems []string) *Counter {
+	return &Counter{counts: generic.FromSlice(elems)}
 }
 
+// NewCounterFromSlice is FromSlice.
 func NewCounterFromSlice(elems []string) *Counter {
-	c := NewCounter()
-	for _, elem := range elems {
-		c.counts[elem]++
-	}
-	return c
+	return FromSlice(elems)
 }
 
+// Update adds one for every element of items.
 func (c *Counter) Update(items []string) {
+	if c.counts == nil {
+		c.counts = generic.New[string]()
+	}
+	c.counts.UpdateSlice(items)
+}
+
+// Subtract removes one for every element of items; counts may go negative,
+// like Python's Counter.subtract. Values whose count reaches zero are dropped.
+func (c *Counter) Subtract(items []string) {
+	if c.counts == nil {
+		c.counts = generic.New[string]()
+	}
 	for _, elem := range items {
-		c.counts[elem]++
+		c.counts.AddN(elem, -1)
 	}
 }
 
+// Count returns how many times value appears (0 if missing).
 func (c *Counter) Count(value string) int {
-	return c.counts[value]
+	return int(c.counts.Get(value))
+}
+
+// Total returns the sum of all positive counts.
+func (c *Counter) Total() int {
+	return int(c.counts.Total())
 }
 
+// MostCommon returns the top n items sorted by count descending, ties in
+// lexical order. If n <= 0 or n exceeds the number of items, all are returned.
 func (c *Counter) MostCommon(n int) []Item {
-	mostCommon := make([]Item, 0, len(c.counts))
-	for k, v := range c.counts {
-		mostCommon = append(mostCommon, Item{Value: k, Count: v})
+	pairs := c.counts.MostCommon(n)
+	out := make([]Item, len(pairs))
+	for i, p := range pairs {
+		out[i] = Item{Value: p.Key, Count: int(p.Count)}
 	}
-	slices.SortFunc(mostCommon, func(i, j Item) int {
-		return j.Count - i.Count
-	})
-	fmt.Println(mostCommon)
-	return mostCommon[:n]
+	return out
+}
+
+// Items returns all items sorted like MostCommon(len(counter)).
+func (c *Counter) Items() []Item {
+	return c.MostCommon(0)
+}
+
+// Elements repeats each value as many times as its count (order undefined);
+// values with zero or negative counts are skipped.
+func (c *Counter) Elements() []string {
+	return c.counts.Elements()
 }
@@ -46,3 +46,34 @@ func TestCounterMostCommonMoreThanSize(t *testing.T) {
 		}
 	}
 }
+
+func TestCounterZeroValueAndSubtract(t *testing.T) {
+	var c Counter
+	if got := c.MostCommon(3); len(got) != 0 {
+		t.Fatalf("expected empty MostCommon, got %+v", got)
+	}
+	c.Subtract([]string{"x"})
+	c.Update([]string{"b", "a", "b", "c", "a"})
+	c.Subtract([]string{"c"})
+
+	if got := c.Count("x"); got != -1 {
+		t.Fatalf("expected x count -1, got %d", got)
+	}
+	if got := c.Total(); got != 4 {
+		t.Fatalf("expected total 4, got %d", got)
+	}
+	// 同频按字典序排列
+	want := []Item{{"a", 2}, {"b", 2}, {"x", -1}} // c 归零后被删除
+	got := c.Items()
+	if len(got) != len(want) {
+		t.Fatalf("items: want %+v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("items[%d] want %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if els := c.Elements(); len(els) != 4 {
+		t.Fatalf("expected 4 elements, got %v", els)
+	}
+}